@@ -4,11 +4,22 @@
 package changeset
 
 import (
+	"encoding"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"net"
+	"net/url"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/zoedsoupe/exo"
 )
@@ -19,12 +30,16 @@ import (
 // It holds the `changes` normally called `attrs`
 // it `errors` for each field and a field that
 // you can always check if a `Changeset[T]` is valid.
+// Every method returns a new `Changeset[T]`, leaving the
+// one it's called on untouched.
 type Changeset[T interface{}] struct {
 	changes     map[string]interface{}
 	params      map[string]interface{}
-	errors      map[string]error
+	errors      map[string][]error
 	validations map[string]Validator
+	validators  map[string][]Validator
 	data        T
+	applied     *bool
 	IsValid     bool
 }
 
@@ -33,9 +48,11 @@ func (c *Changeset[T]) Error() string {
 
 	out.WriteString("Changeset has errors:\n\t")
 
-	for field, err := range c.GetErrors() {
-		msg := fmt.Sprintf("%s: %s\n\t", field, err)
-		out.WriteString(msg)
+	for field, errs := range c.errors {
+		for _, err := range errs {
+			msg := fmt.Sprintf("%s: %s\n\t", field, err)
+			out.WriteString(msg)
+		}
 	}
 
 	return out.String()
@@ -43,23 +60,326 @@ func (c *Changeset[T]) Error() string {
 
 // Convenience to transform a `Changeset[T]` to
 // a String JSON ready to be sent as HTTP server
-// response.
+// response. Many errors on the same field have
+// their messages joined by commas.
 func (c *Changeset[T]) ErrorJSON() map[string]string {
 	var final = make(map[string]string)
-	for field, err := range c.GetErrors() {
-		final[field] = err.Error()
+	for field, errs := range c.errors {
+		msgs := make([]string, len(errs))
+		for i, err := range errs {
+			msgs[i] = err.Error()
+		}
+		final[field] = strings.Join(msgs, ", ")
 	}
 
 	return final
 }
 
+// Formats the `Changeset[T]` errors as a RFC 7807 problem
+// document, ready to be sent as an HTTP 422 response body.
+// Each field error becomes an `invalid-params` entry,
+// sorted by field name.
+func (c *Changeset[T]) ProblemJSON(typeURI, title string) map[string]interface{} {
+	params := make([]map[string]string, 0, len(c.errors))
+	for _, field := range c.ErrorKeys() {
+		for _, err := range c.errors[field] {
+			params = append(params, map[string]string{"name": field, "reason": err.Error()})
+		}
+	}
+
+	return map[string]interface{}{
+		"type":           typeURI,
+		"title":          title,
+		"status":         422,
+		"invalid-params": params,
+	}
+}
+
 // Fiven a data type and a map of attributes, filter
 // parameters that exists as field on the data type.
+// String parameters targeting fields whose type implements
+// `encoding.TextUnmarshaler` or `json.Unmarshaler` are decoded
+// with it, adding the decoding failures as errors.
+// Parameters are matched by the field `exo` tag, then by its
+// `json` tag and then by the field name, while the changes are
+// always keyed by the field name.
 // If the value of the parameter mismatch the data type field,
 // an error is added to the Changeset and it is amrked as invalid.
 func Cast[T interface{}](params map[string]interface{}) Changeset[T] {
 	var s T
+	return cast(s, params, CastOptions{})
+}
+
+// Same as Cast but recovers from any panic, like when the
+// data type isn't a struct, returning it as an error instead.
+// Useful when the data type is only known at runtime.
+func SafeCast[T interface{}](params map[string]interface{}) (c Changeset[T], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cast failed: %v", r)
+		}
+	}()
+
+	return Cast[T](params), nil
+}
+
+// Same as Cast but, before adding a type mismatch error,
+// tries to convert the parameter into the data type field.
+// String parameters, like the ones from query strings or form
+// posts, are parsed with `strconv` into numeric and bool fields.
+// Numeric parameters are read as seconds for `time.Duration`
+// fields and as Unix timestamps for `time.Time` fields.
+func CastCoerce[T interface{}](params map[string]interface{}) Changeset[T] {
+	var s T
+	return cast(s, params, CastOptions{Coerce: true})
+}
+
+// Same as CastCoerce but casts query parameters, like the ones
+// of a search page. Repeated keys, like "tags=a&tags=b", are cast
+// into slice fields while only the first value of a key is cast
+// into other fields. Keys are matched like `Cast` does.
+func CastQuery[T interface{}](q url.Values) Changeset[T] {
+	var s T
+	params := make(map[string]interface{}, len(q))
+
+	for _, f := range exo.StructFields(s) {
+		key := exo.ParamName(f)
+		values, ok := q[key]
+		if key == "" || !ok || len(values) == 0 {
+			continue
+		}
+
+		if f.Type.Kind() == reflect.Slice {
+			params[key] = values
+		} else {
+			params[key] = values[0]
+		}
+	}
+
+	return cast(s, params, CastOptions{Coerce: true})
+}
+
+// Casts the nested params of an associated struct field, like
+// an order address, with the given cast function, usually
+// `Cast[C]` or a function validating the child changeset too.
+// A valid child is applied and put as the field change, while
+// the errors of an invalid one are added to the parent under
+// namespaced keys, like "Address.Street", marking it as invalid.
+func CastAssoc[C interface{}, T interface{}](c Changeset[T], field string, params map[string]interface{}, castFn func(map[string]interface{}) Changeset[C]) Changeset[T] {
+	c = c.clone()
+
+	child := castFn(params)
+
+	if !child.IsValid {
+		for key, errs := range child.errors {
+			key = field + "." + key
+			c.errors[key] = append(c.errors[key], errs...)
+		}
+		c.IsValid = false
+		return c
+	}
+
+	s, err := ApplyNew(child)
+	if err != nil {
+		c = c.AddError(field, err)
+		c.IsValid = false
+		return c
+	}
+
+	return c.PutChange(field, s)
+}
+
+// Describes a parameter of a `SchemaValidator`: its JSON type,
+// one of "string", "number", "integer", "boolean", "array" or
+// "object", and whether it's required.
+type SchemaField struct {
+	Type     string
+	Required bool
+}
+
+// A lightweight schema of parameters, keyed by parameter name.
+type SchemaValidator map[string]SchemaField
+
+// Check if the param is of the given schema type.
+func (sf SchemaField) matches(param interface{}) bool {
+	v := reflect.ValueOf(param)
+
+	switch sf.Type {
+	case "string":
+		return v.Kind() == reflect.String
+	case "number":
+		_, ok := toFloat(param)
+		return ok
+	case "integer":
+		n, ok := toFloat(param)
+		return ok && n == math.Trunc(n)
+	case "boolean":
+		return v.Kind() == reflect.Bool
+	case "array":
+		return v.Kind() == reflect.Slice || v.Kind() == reflect.Array
+	case "object":
+		return v.Kind() == reflect.Map || v.Kind() == reflect.Struct
+	}
+
+	return false
+}
+
+// Same as Cast but first checks the params against the schema,
+// bridging untyped JSON and changesets. Missing required params
+// and params of the wrong type are added as errors on their
+// fields, the latter left out of the cast.
+func ValidateSchema[T interface{}](params map[string]interface{}, schema SchemaValidator) Changeset[T] {
+	var s T
+	names := make(map[string]string)
+	for _, f := range exo.StructFields(s) {
+		names[exo.ParamName(f)] = f.Name
+	}
+
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	valid := make(map[string]interface{}, len(params))
+	for key, param := range params {
+		valid[key] = param
+	}
+
+	var errs []FieldError
+	for _, key := range keys {
+		sf := schema[key]
+		field, ok := names[key]
+		if !ok {
+			field = key
+		}
+
+		param, exists := params[key]
+		switch {
+		case !exists || param == nil:
+			if sf.Required {
+				errs = append(errs, FieldError{Field: field, Err: newValidationError("required", nil, "is required")})
+			}
+		case !sf.matches(param):
+			delete(valid, key)
+			details := map[string]interface{}{"type": sf.Type}
+			errs = append(errs, FieldError{Field: field, Err: newValidationError("type", details, "must be of type %s", sf.Type)})
+		}
+	}
+
+	c := cast(s, valid, CastOptions{})
+	c.params = params
+
+	for _, fe := range errs {
+		c.errors[fe.Field] = append(c.errors[fe.Field], fe.Err)
+		c.IsValid = false
+	}
+
+	return c
+}
+
+// Same as Cast but renames the parameters keys before casting,
+// given a mapping of parameters keys to data type fields names.
+// A renamed key takes precedence over a parameter already named
+// after the field. Targets are resolved to the fields param names,
+// so tagged fields can be renamed into too, and fields tagged "-"
+// are never cast. `GetParams` still returns the raw parameters.
+func CastRenamed[T interface{}](params map[string]interface{}, mapping map[string]string) Changeset[T] {
+	var s T
+	names := make(map[string]string)
+	for _, f := range exo.StructFields(s) {
+		names[f.Name] = exo.ParamName(f)
+	}
+
+	renamed := make(map[string]interface{}, len(params))
+
+	for key, value := range params {
+		if _, ok := mapping[key]; !ok {
+			renamed[key] = value
+		}
+	}
+
+	for key, field := range mapping {
+		value, ok := params[key]
+		if !ok {
+			continue
+		}
+
+		if name, found := names[field]; found {
+			if name == "" {
+				continue
+			}
+			field = name
+		}
+
+		renamed[field] = value
+	}
+
+	c := Cast[T](renamed)
+	c.params = params
+	return c
+}
+
+// Same as Cast but seeds the changeset with an existing instance
+// of the data type, like a record loaded from a database, so
+// validations can compare the changes against the current data.
+func CastFrom[T interface{}](data T, params map[string]interface{}) Changeset[T] {
+	return cast(data, params, CastOptions{})
+}
+
+// Same as Cast but the params are read from an existing instance
+// of the data type with `exo.ToMap`, so every exported field,
+// but the ones tagged with "-", becomes a change, like for
+// update forms.
+func CastStruct[T interface{}](s T) Changeset[T] {
+	m := exo.ToMap(s)
+	params := make(map[string]interface{}, len(m))
+
+	for _, f := range exo.StructFields(s) {
+		value, ok := m[f.Name]
+		if key := exo.ParamName(f); key != "" && ok {
+			params[key] = value
+		}
+	}
+
+	var data T
+	return cast(data, params, CastOptions{})
+}
+
+// Same as CastFrom but only stages the changes that differ from
+// the current data, like when reconciling a record with external
+// params. Changes deep equal to their current value are dropped.
+func CastDiff[T interface{}](current T, params map[string]interface{}) Changeset[T] {
+	c := CastFrom(current, params)
+
+	r := reflect.ValueOf(current)
+	for field, change := range c.changes {
+		if f := exo.FieldByName(r, field); f.IsValid() && reflect.DeepEqual(f.Interface(), change) {
+			delete(c.changes, field)
+		}
+	}
+
+	return c
+}
+
+// Options to tune how `CastWith` handles the parameters.
+type CastOptions struct {
+	// Converts parameters mismatching the field type,
+	// check `CastCoerce` for more information.
+	Coerce bool
+	// Skips empty string parameters instead of staging them,
+	// like the untouched fields submitted by HTML forms.
+	EmptyStringAsAbsent bool
+}
+
+// Same as Cast but handles the parameters according
+// to the given `CastOptions`.
+func CastWith[T interface{}](params map[string]interface{}, opts CastOptions) Changeset[T] {
+	var s T
+	return cast(s, params, opts)
+}
 
+func cast[T interface{}](s T, params map[string]interface{}, opts CastOptions) Changeset[T] {
 	t := reflect.TypeOf(s)
 	if t.Kind() != reflect.Struct {
 		panic(fmt.Errorf("argument is not a struct"))
@@ -69,452 +389,2811 @@ func Cast[T interface{}](params map[string]interface{}) Changeset[T] {
 	c.params = params
 	c.data = s
 	c.IsValid = true
-	c.errors = make(map[string]error)
+	c.errors = make(map[string][]error)
 	c.validations = make(map[string]Validator)
+	c.validators = make(map[string][]Validator)
 	c.changes = make(map[string]interface{})
+	c.applied = new(bool)
 
 	for _, f := range exo.StructFields(s) {
 		field := f.Name
-		change, ok := params[field]
-		if !ok {
+		key := exo.ParamName(f)
+		if key == "" {
+			continue
+		}
+
+		change, ok := params[key]
+		if !ok || (opts.EmptyStringAsAbsent && change == "") {
+			continue
+		}
+
+		// explicit nils, like JSON nulls, are only staged
+		// on fields that can hold them and skipped otherwise
+		if change == nil {
+			if isNilable(f.Type) {
+				c.changes[field] = reflect.Zero(f.Type).Interface()
+			}
 			continue
 		}
 
-		sType := f.Type.String()
-		cType := reflect.TypeOf(change).String()
-		if cType != sType {
+		v, err := castChange(change, f.Type, opts.Coerce)
+		if err != nil {
 			c.IsValid = false
-			msg := fmt.Errorf("type mismatch: expect %s got %s", sType, cType)
-			c.AddError(field, msg)
-		} else {
-			c.changes[field] = change
+			c = c.AddError(field, &CastError{Err: err})
+			continue
 		}
+
+		c.changes[field] = v
 	}
 
 	return c
 }
 
-// Same as Apply but handle a new instance of the desired
-// data structure.
-func ApplyNew[T interface{}](c Changeset[T]) (T, error) {
-	var s = c.data
-	err := Apply(&s, c)
-	return s, err
+// Wraps the errors found while casting parameters, like type
+// mismatches, so malformed input can be told apart from
+// validation errors. Check `CastErrors` for more information.
+type CastError struct {
+	Err error
 }
 
-// Given an already existence instance of the data type used
-// to generate the Changeset as a pointer, and the Changeset
-// it self, apply all changes to the instance.
-// Note that this function panic if given an invalid data type.
-func Apply[T interface{}](s *T, c Changeset[T]) error {
-	t := reflect.ValueOf(s)
-	if t.Kind() != reflect.Ptr {
-		panic(fmt.Errorf("argument to Apply is not a pointer to a struct"))
-	}
-	if t.Elem().Type().Kind() != reflect.Struct {
-		panic(fmt.Errorf("argument to Apply is not a struct"))
+func (ce *CastError) Error() string {
+	return ce.Err.Error()
+}
+
+func (ce *CastError) Unwrap() error {
+	return ce.Err
+}
+
+// Checks that a parameter matches the field type, optionally
+// trying to convert it before giving up.
+func castChange(change interface{}, t reflect.Type, coerce bool) (interface{}, error) {
+	sType := t.String()
+	if change == nil {
+		return nil, fmt.Errorf("type mismatch: expect %s got nil", sType)
 	}
 
-	if !c.IsValid {
-		return &c
+	cType := reflect.TypeOf(change).String()
+	if cType == sType {
+		return change, nil
 	}
 
-	r := reflect.ValueOf(s).Elem()
-	for key, value := range c.changes {
-		f := r.FieldByName(key)
-		if !(f.IsValid() && f.CanSet()) {
-			continue
+	if str, isString := change.(string); isString {
+		if v, ok, err := unmarshalChange(str, t); ok {
+			return v, err
 		}
+	}
 
-		val := reflect.ValueOf(value)
-		if !val.Type().AssignableTo(f.Type()) {
-			msg := fmt.Errorf("type mismatch expected %s got %s", key, val.Type().String())
-			c.AddError(key, msg)
-			return &c
+	if coerce {
+		if v, ok, err := coerceChange(change, t); ok {
+			return v, err
 		}
+	}
 
-		f.Set(val)
+	return nil, fmt.Errorf("type mismatch: expect %s got %s", sType, cType)
+}
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// Check if a value of the given type can be nil.
+func isNilable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return true
 	}
 
-	return nil
+	return false
 }
 
-// Adds a new error on the given field. Note that if
-// already exists an error on the given field, it will
-// be overwritten.
-func (c Changeset[T]) AddError(field string, err error) Changeset[T] {
-	c.errors[field] = err
-	return c
+// Builds a value of the given field type from a string parameter
+// when the type implements `encoding.TextUnmarshaler` or
+// `json.Unmarshaler`, returning false when it implements neither.
+func unmarshalChange(str string, t reflect.Type) (interface{}, bool, error) {
+	ptr := reflect.PointerTo(t)
+	v := reflect.New(t)
+
+	switch {
+	case ptr.Implements(textUnmarshalerType):
+		if err := v.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str)); err != nil {
+			return nil, true, fmt.Errorf("is invalid: %w", err)
+		}
+	case ptr.Implements(jsonUnmarshalerType):
+		b, _ := json.Marshal(str)
+		if err := v.Interface().(json.Unmarshaler).UnmarshalJSON(b); err != nil {
+			return nil, true, fmt.Errorf("is invalid: %w", err)
+		}
+	default:
+		return nil, false, nil
+	}
+
+	return v.Elem().Interface(), true, nil
 }
 
-// Writes a change into the given field. The only validation that
-// is made is the type matching for the given data type field.
-// Note that if a change is already present of the changes map,
-// it will be overwritten.
-// This function is more suited for internal usage into an application.
-func (c Changeset[T]) PutChange(field string, change interface{}) Changeset[T] {
-	sfs := exo.StructFields(c.data)
-	var fields = make([]string, len(sfs))
+// Tries to convert a parameter into the given field type,
+// returning false when there's no known conversion.
+// Slices are converted element by element.
+// Numeric parameters targeting integer fields are checked
+// against overflow and truncation.
+func coerceChange(change interface{}, t reflect.Type) (interface{}, bool, error) {
+	if v := reflect.ValueOf(change); t.Kind() == reflect.Slice && v.Kind() == reflect.Slice {
+		out := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			elem, err := castChange(v.Index(i).Interface(), t.Elem(), true)
+			if err != nil {
+				return nil, true, fmt.Errorf("at index %d: %w", i, err)
+			}
+			out.Index(i).Set(reflect.ValueOf(elem))
+		}
+		return out.Interface(), true, nil
+	}
 
-	for i, f := range sfs {
-		fields[i] = f.Name
+	if str, isString := change.(string); isString {
+		if t.Kind() == reflect.Bool {
+			b, err := strconv.ParseBool(str)
+			if err != nil {
+				return nil, false, nil
+			}
+			return reflect.ValueOf(b).Convert(t).Interface(), true, nil
+		}
+
+		parsed, ok := parseNumber(str)
+		if !ok {
+			return nil, false, nil
+		}
+		change = parsed
 	}
 
-	for i, f := range fields {
-		if field == f {
-			val := reflect.ValueOf(change)
-			sf := sfs[i]
+	n, ok := toFloat(change)
+	if !ok {
+		return nil, false, nil
+	}
 
-			if !val.Type().AssignableTo(sf.Type) {
-				c.IsValid = false
-				c.errors[field] = fmt.Errorf("type mismatch, expected %s got %s", sf.Type.String(), val.Type().String())
-				return c
-			}
+	switch t {
+	case durationType:
+		return time.Duration(n * float64(time.Second)), true, nil
+	case timeType:
+		sec, frac := math.Modf(n)
+		return time.Unix(int64(sec), int64(frac*float64(time.Second))), true, nil
+	}
 
-			c.changes[field] = change
-			return c
+	if _, ok := intTypes[t.Kind()]; ok {
+		v, err := toInt(change, t)
+		if err != nil {
+			return nil, true, err
 		}
+		return v.Interface(), true, nil
 	}
 
-	c.IsValid = false
-	c.errors[field] = fmt.Errorf("%s is invalid", field)
-	return c
+	if k := t.Kind(); k == reflect.Float32 || k == reflect.Float64 {
+		out := reflect.New(t).Elem()
+		out.SetFloat(n)
+		return out.Interface(), true, nil
+	}
+
+	return nil, false, nil
 }
 
-// Given an callback receives the current change and would
-// return a change and an optional error, updates or transform
-// the change for the given field.
-// Note that the current change can be possibly a zero value
-// or `nil`. Also note that this function will behave like
-// `PutChange`, only make type assertions for fields and no
-// additional validation.
-func (c Changeset[T]) UpdateChange(field string, cb func(interface{}) (interface{}, error)) Changeset[T] {
-	v, err := cb(c.changes[field])
-	if err != nil {
-		c.AddError(field, err)
-		c.IsValid = false
-		return c
+// Parses a string as an int64, an uint64 or a float64,
+// keeping the precision of integers.
+func parseNumber(s string) (interface{}, bool) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, true
 	}
-	return c.PutChange(field, v)
+
+	if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+		return u, true
+	}
+
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, true
+	}
+
+	return nil, false
 }
 
-// Interface to define custom validations for changesets.
-// Check `ValidateChange` for more information.
-type Validator interface {
-	Validate(field string, value interface{}) (bool, error)
+var intTypes = map[reflect.Kind]reflect.Type{
+	reflect.Int:    reflect.TypeOf(int(0)),
+	reflect.Int8:   reflect.TypeOf(int8(0)),
+	reflect.Int16:  reflect.TypeOf(int16(0)),
+	reflect.Int32:  reflect.TypeOf(int32(0)),
+	reflect.Int64:  reflect.TypeOf(int64(0)),
+	reflect.Uint:   reflect.TypeOf(uint(0)),
+	reflect.Uint8:  reflect.TypeOf(uint8(0)),
+	reflect.Uint16: reflect.TypeOf(uint16(0)),
+	reflect.Uint32: reflect.TypeOf(uint32(0)),
+	reflect.Uint64: reflect.TypeOf(uint64(0)),
 }
 
-// Validates that a given change has the desired length.
-// It works on string, map and slice types.
-// If you want an **exact** length, give the `Min` and `Max`
-// the same value.
-type LengthValidator struct {
-	Min int
-	Max int
+// Converts a numeric value into the given integer type,
+// erroring when it would overflow or lose its fractional part.
+func toInt(v interface{}, t reflect.Type) (reflect.Value, error) {
+	val := reflect.ValueOf(v)
+	out := reflect.New(t).Elem()
+	signed := out.CanInt()
+	outOfRange := fmt.Errorf("is out of range for %s", t.Kind())
+
+	switch {
+	case val.CanInt():
+		i := val.Int()
+		if signed && !out.OverflowInt(i) {
+			out.SetInt(i)
+			return out, nil
+		}
+		if !signed && i >= 0 && !out.OverflowUint(uint64(i)) {
+			out.SetUint(uint64(i))
+			return out, nil
+		}
+		return out, outOfRange
+	case val.CanUint():
+		u := val.Uint()
+		if signed && u <= math.MaxInt64 && !out.OverflowInt(int64(u)) {
+			out.SetInt(int64(u))
+			return out, nil
+		}
+		if !signed && !out.OverflowUint(u) {
+			out.SetUint(u)
+			return out, nil
+		}
+		return out, outOfRange
+	case val.CanFloat():
+		f := val.Float()
+		if f != math.Trunc(f) {
+			return out, fmt.Errorf("is not an integer")
+		}
+		if signed && f >= math.MinInt64 && f < math.MaxInt64 && !out.OverflowInt(int64(f)) {
+			out.SetInt(int64(f))
+			return out, nil
+		}
+		if !signed && f >= 0 && f < math.MaxUint64 && !out.OverflowUint(uint64(f)) {
+			out.SetUint(uint64(f))
+			return out, nil
+		}
+		return out, outOfRange
+	}
+
+	return out, fmt.Errorf("isn't a Number %v", v)
 }
 
-func (lv LengthValidator) Validate(field string, v interface{}) (bool, error) {
-	var l int
-	t := reflect.TypeOf(v).String()
-	var msg string
+// Reads a value of any numeric kind as a float64.
+func toFloat(v interface{}) (float64, bool) {
+	val := reflect.ValueOf(v)
+
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(val.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(val.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return val.Float(), true
+	}
 
-	switch t {
-	case "string":
-		l = len(v.(string))
-		msg = "should be %s %d characters"
-	case "slice":
-		l = len(v.([]interface{}))
-		msg = "should have %s %d items"
-	case "map":
-		l = len(v.(map[interface{}]interface{}))
-		msg = "should have %s %d elements"
-	default:
-		l = -1
+	return 0, false
+}
+
+// Converts a value of any numeric kind into the given `Number`
+// type, so validators parameterized on `int` accept `int64`
+// changes. Conversions losing precision, like a fractional float
+// into an integer or an out of range value, return false.
+func toNumber[T Number](v interface{}) (T, bool) {
+	if n, ok := v.(T); ok {
+		return n, true
+	}
+
+	var n T
+	f, ok := toFloat(v)
+	if !ok {
+		return n, false
+	}
+
+	out := reflect.ValueOf(v).Convert(reflect.TypeOf(n))
+	if back, _ := toFloat(out.Interface()); back != f {
+		return n, false
+	}
+
+	return out.Interface().(T), true
+}
+
+// Same as toNumber but for `Integer` types, rejecting floats
+// even when they hold a whole value.
+func toInteger[T Integer](v interface{}) (T, bool) {
+	switch v.(type) {
+	case float32, float64:
+		var n T
+		return n, false
+	}
+
+	return toNumber[T](v)
+}
+
+// Same as Apply but handle a new instance of the desired
+// data structure.
+func ApplyNew[T interface{}](c Changeset[T]) (T, error) {
+	var s = c.data
+	err := Apply(&s, c)
+	return s, err
+}
+
+// Same as ApplyNew but returns a pointer to the new instance,
+// which is nil when the changeset is invalid, along with a
+// pointer to the changeset itself so its errors can be read.
+func ApplyRef[T interface{}](c Changeset[T]) (*T, *Changeset[T]) {
+	s, err := ApplyNew(c)
+	if err != nil {
+		var ch *Changeset[T]
+		if errors.As(err, &ch) {
+			return nil, ch
+		}
+		return nil, &c
+	}
+
+	return &s, &c
+}
+
+// Same as ApplyNew but marshals the new instance to JSON,
+// useful when the result goes straight into a cache or a
+// response body. Invalid changesets return themselves as error.
+func ApplyJSON[T interface{}](c Changeset[T]) ([]byte, error) {
+	s, err := ApplyNew(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(s)
+}
+
+// Summary of what `ApplyWithReport` did: the sorted fields
+// whose changes were set, the sorted fields whose changes were
+// left out and the validators run on the changeset by field.
+type ApplyReport struct {
+	Applied    []string
+	Skipped    []string
+	Validators map[string]Validator
+}
+
+// Same as ApplyNew but also return an `ApplyReport`, useful for
+// auditing or debugging a changeset lifecycle in one call.
+// When the changeset is invalid, every change is skipped.
+func ApplyWithReport[T interface{}](c Changeset[T]) (T, ApplyReport, error) {
+	report := ApplyReport{Validators: make(map[string]Validator, len(c.validations))}
+	for field, v := range c.validations {
+		report.Validators[field] = v
+	}
+
+	s, err := ApplyNew(c)
+
+	fields := make([]string, 0, len(c.changes))
+	for field := range c.changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	if err != nil {
+		report.Skipped = fields
+		return s, report, err
+	}
+
+	t := reflect.TypeOf(s)
+	for _, field := range fields {
+		if _, ok := t.FieldByName(field); ok {
+			report.Applied = append(report.Applied, field)
+		} else {
+			report.Skipped = append(report.Skipped, field)
+		}
+	}
+
+	return s, report, nil
+}
+
+// Same as ApplyNew but return the changes as a map keyed by
+// field name instead of setting them on a struct, for code
+// persisting maps. Invalid changesets return themselves as error
+// and, like ApplyNew, the changeset is marked as applied.
+func ApplyMap[T interface{}](c Changeset[T]) (map[string]interface{}, error) {
+	if c.IsApplied() {
+		return nil, ErrAlreadyApplied
+	}
+
+	if !c.IsValid {
+		return nil, &c
+	}
+
+	out := make(map[string]interface{}, len(c.changes))
+	for field, change := range c.changes {
+		out[field] = change
+	}
+
+	if c.applied != nil {
+		*c.applied = true
+	}
+
+	return out, nil
+}
+
+// Given an already existence instance of the data type used
+// to generate the Changeset as a pointer, and the Changeset
+// it self, apply all changes to the instance.
+// Note that this function panic if given an invalid data type.
+func Apply[T interface{}](s *T, c Changeset[T]) error {
+	return apply(s, c, false, nil)
+}
+
+// Same as Apply but deep copies slice, map and pointer changes
+// before setting them, so the instance doesn't share backing
+// storage with the changes and later mutations on the input
+// don't leak into it. Note that cyclic values aren't supported.
+func DeepApply[T interface{}](s *T, c Changeset[T]) error {
+	return apply(s, c, true, nil)
+}
+
+// Same as Apply but only sets the changes whose field name
+// satisfies the predicate. The other changes remain staged,
+// but like Apply the changeset is marked as applied, so it must
+// be `Reset` before applying them.
+func ApplyWhere[T interface{}](s *T, c Changeset[T], pred func(field string) bool) error {
+	return apply(s, c, false, pred)
+}
+
+// Same as Apply but runs again every validator of each field
+// right before setting it, guarding against changes altered
+// after being validated. Fields failing now are skipped and
+// returned as `FieldError`s, joined with `errors.Join`. The
+// changeset is marked as applied even when some fields fail.
+func ApplyRevalidated[T interface{}](s *T, c Changeset[T]) error {
+	if c.IsApplied() {
+		return ErrAlreadyApplied
+	}
+
+	failed := make(map[string]error)
+	for field, change := range c.changes {
+		for _, v := range c.validators[field] {
+			if ok, err := c.validate(field, v, change); !ok {
+				failed[field] = err
+				break
+			}
+		}
+	}
+
+	if len(failed) == 0 {
+		return Apply(s, c)
+	}
+
+	err := apply(s, c, false, func(field string) bool {
+		_, skip := failed[field]
+		return !skip
+	})
+	if err != nil {
+		return err
+	}
+
+	fields := make([]string, 0, len(failed))
+	for field := range failed {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	errs := make([]error, len(fields))
+	for i, field := range fields {
+		errs[i] = FieldError{Field: field, Err: failed[field]}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Best effort version of Apply, sets only the changes of fields
+// without errors, even if the changeset is invalid, and return
+// the sorted keys with errors. Fields with indexed or nested
+// errors, like "Tags.1", are skipped as well. Unlike the other
+// Apply functions, it neither checks nor marks the changeset as
+// applied, as it's meant for previews like `ChangesAsStruct`.
+func ApplyValid[T interface{}](s *T, c Changeset[T]) []string {
+	r := reflect.ValueOf(s).Elem()
+	for key, value := range c.changes {
+		if c.hasErrors(key) {
+			continue
+		}
+
+		f := exo.SettableFieldByName(r, key)
+		if !(f.IsValid() && f.CanSet()) {
+			continue
+		}
+
+		if val, ok := settable(value, f.Type()); ok {
+			f.Set(val)
+		}
+	}
+
+	return c.ErrorKeys()
+}
+
+func apply[T interface{}](s *T, c Changeset[T], clone bool, pred func(string) bool) error {
+	t := reflect.ValueOf(s)
+	if t.Kind() != reflect.Ptr {
+		panic(fmt.Errorf("argument to Apply is not a pointer to a struct"))
+	}
+	if t.Elem().Type().Kind() != reflect.Struct {
+		panic(fmt.Errorf("argument to Apply is not a struct"))
+	}
+
+	if c.IsApplied() {
+		return ErrAlreadyApplied
+	}
+
+	if !c.IsValid {
+		return &c
+	}
+
+	r := reflect.ValueOf(s).Elem()
+	for key, value := range c.changes {
+		if pred != nil && !pred(key) {
+			continue
+		}
+
+		f := exo.SettableFieldByName(r, key)
+		if !(f.IsValid() && f.CanSet()) {
+			continue
+		}
+
+		if clone {
+			value = exo.Clone(value)
+		}
+
+		val, ok := settable(value, f.Type())
+		if !ok {
+			msg := fmt.Errorf("type mismatch expected %s got %T", key, value)
+			c = c.AddError(key, msg)
+			return &c
+		}
+
+		f.Set(val)
+	}
+
+	if c.applied != nil {
+		*c.applied = true
+	}
+
+	return nil
+}
+
+// Converts a change into a value that can be set on a field of
+// the given type. A nil change becomes the zero value of nilable
+// types and a change of a pointer field element type is copied
+// and its address taken, so `string` changes fit `*string` fields.
+// Return false when the change doesn't fit the field.
+func settable(change interface{}, t reflect.Type) (reflect.Value, bool) {
+	val := reflect.ValueOf(change)
+
+	switch {
+	case !val.IsValid():
+		return reflect.Zero(t), isNilable(t)
+	case val.Type().AssignableTo(t):
+		return val, true
+	case t.Kind() == reflect.Ptr && val.Type().AssignableTo(t.Elem()):
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(val)
+		return ptr, true
+	}
+
+	return val, false
+}
+
+// Returned when applying a changeset that was already applied.
+var ErrAlreadyApplied = errors.New("changeset already applied")
+
+// Check if the changeset was already applied by any of the
+// Apply functions, like `Apply`, `ApplyNew` or `ApplyWhere`.
+// Applying it again returns `ErrAlreadyApplied` until it's `Reset`.
+// Changesets derived from it, like with `PutChange`, are new
+// changesets and aren't marked as applied.
+func (c Changeset[T]) IsApplied() bool {
+	return c.applied != nil && *c.applied
+}
+
+// Return the changeset marked as not applied, so it can
+// be applied again.
+func (c Changeset[T]) Reset() Changeset[T] {
+	c.applied = new(bool)
+	return c
+}
+
+// Return a copy of the changeset that doesn't share its changes,
+// errors and validations with the original, so every method
+// returns a new changeset and never modifies the one it's called
+// on. The copy also gets its own applied flag, unset, so
+// applying the original doesn't mark changesets derived from it.
+func (c Changeset[T]) clone() Changeset[T] {
+	changes := make(map[string]interface{}, len(c.changes))
+	for field, change := range c.changes {
+		changes[field] = change
+	}
+
+	errs := make(map[string][]error, len(c.errors))
+	for field, e := range c.errors {
+		errs[field] = append([]error(nil), e...)
+	}
+
+	validations := make(map[string]Validator, len(c.validations))
+	for field, v := range c.validations {
+		validations[field] = v
+	}
+
+	validators := make(map[string][]Validator, len(c.validators))
+	for field, vs := range c.validators {
+		validators[field] = append([]Validator(nil), vs...)
+	}
+
+	c.changes, c.errors, c.validations, c.validators = changes, errs, validations, validators
+	c.applied = new(bool)
+	return c
+}
+
+// Adds a new error on the given field. Note that if
+// already exists an error on the given field, both
+// are kept.
+func (c Changeset[T]) AddError(field string, err error) Changeset[T] {
+	c = c.clone()
+
+	c.errors[field] = append(c.errors[field], err)
+	return c
+}
+
+// Same as AddError, but also marks the changeset as invalid.
+// Meant for folding errors that surface after applying the
+// changeset, like database constraint violations, back into
+// it so they're rendered like any other field error.
+func (c Changeset[T]) AddDBError(field string, err error) Changeset[T] {
+	c = c.AddError(field, err)
+	c.IsValid = false
+	return c
+}
+
+// Adds a friendly error on the given field for a database
+// constraint violation, guessing its kind from the usual
+// constraint names suffixes: "_key", "_pkey" or a name with
+// "unique" for unique constraints, "_fkey" for foreign keys,
+// "_check" for check constraints and "_excl" for exclusions.
+// The error is a `ValidationError` keyed "unique", "foreign_key",
+// "check", "exclusion_constraint" or "constraint" otherwise.
+func (c Changeset[T]) FromConstraintError(field, constraintName string) Changeset[T] {
+	name := strings.ToLower(constraintName)
+
+	key, msg := "constraint", "is invalid"
+	switch {
+	case strings.HasSuffix(name, "_fkey"):
+		key, msg = "foreign_key", "does not exist"
+	case strings.HasSuffix(name, "_key"), strings.HasSuffix(name, "_pkey"), strings.Contains(name, "unique"):
+		key, msg = "unique", "has already been taken"
+	case strings.HasSuffix(name, "_excl"):
+		key, msg = "exclusion_constraint", "violates an exclusion constraint"
+	case strings.HasSuffix(name, "_check"):
+		key = "check"
+	}
+
+	params := map[string]interface{}{"constraint": constraintName}
+	return c.AddDBError(field, newValidationError(key, params, msg))
+}
+
+// Removes the errors on the given field, keeping its change.
+// If no errors remain, the changeset is marked as valid again.
+func (c Changeset[T]) ClearError(field string) Changeset[T] {
+	c = c.clone()
+
+	delete(c.errors, field)
+
+	if len(c.errors) == 0 {
+		c.IsValid = true
+	}
+
+	return c
+}
+
+// Return the changeset with its errors and validations
+// cleared, marked as valid, but keeping its changes.
+// Useful for re-validating after transforming the changes.
+func (c Changeset[T]) ResetValidations() Changeset[T] {
+	c = c.clone()
+	c.errors = make(map[string][]error)
+	c.validations = make(map[string]Validator)
+	c.validators = make(map[string][]Validator)
+	c.IsValid = true
+	return c
+}
+
+// Writes a change into the given field. The only validation that
+// is made is the type matching for the given data type field.
+// Note that if a change is already present of the changes map,
+// it will be overwritten.
+// This function is more suited for internal usage into an application.
+func (c Changeset[T]) PutChange(field string, change interface{}) Changeset[T] {
+	c = c.clone()
+
+	sfs := exo.StructFields(c.data)
+	var fields = make([]string, len(sfs))
+
+	for i, f := range sfs {
+		fields[i] = f.Name
+	}
+
+	for i, f := range fields {
+		if field == f {
+			sf := sfs[i]
+
+			if _, ok := settable(change, sf.Type); !ok {
+				c.IsValid = false
+				details := map[string]interface{}{"type": sf.Type.String()}
+				c.errors[field] = append(c.errors[field], newValidationError("type", details, "type mismatch, expected %s got %T", sf.Type.String(), change))
+				return c
+			}
+
+			c.changes[field] = change
+			return c
+		}
+	}
+
+	c.IsValid = false
+	c.errors[field] = append(c.errors[field], newValidationError("invalid", nil, "%s is invalid", field))
+	return c
+}
+
+// Writes every entry of the given map as a change, like calling
+// `PutChange` for each of them. Type mismatches are accumulated
+// as errors on their fields, marking the changeset as invalid.
+func (c Changeset[T]) PutChanges(changes map[string]interface{}) Changeset[T] {
+	for field, change := range changes {
+		c = c.PutChange(field, change)
+	}
+
+	return c
+}
+
+// Reads the given fields from another struct, or pointer to a
+// struct, like a DTO, and writes them as changes with `PutChange`.
+// Fields missing on the source are added as errors.
+func (c Changeset[T]) PutChangesFromStruct(src interface{}, fields []string) Changeset[T] {
+	c = c.clone()
+
+	r := reflect.Indirect(reflect.ValueOf(src))
+
+	for _, field := range fields {
+		var f reflect.Value
+		if r.Kind() == reflect.Struct {
+			f = exo.FieldByName(r, field)
+		}
+
+		if !f.IsValid() || !f.CanInterface() {
+			c.errors[field] = append(c.errors[field], newValidationError("missing", nil, "doesn't exist on %T", src))
+			c.IsValid = false
+			continue
+		}
+
+		c = c.PutChange(field, f.Interface())
+	}
+
+	return c
+}
+
+// Removes the change on the given field along with its errors,
+// like the ones from a failed `PutChange`. If no errors remain,
+// the changeset is marked as valid again.
+func (c Changeset[T]) DeleteChange(field string) Changeset[T] {
+	c = c.clone()
+
+	delete(c.changes, field)
+	return c.ClearError(field)
+}
+
+// Given an callback receives the current change and would
+// return a change and an optional error, updates or transform
+// the change for the given field.
+// Note that the current change can be possibly a zero value
+// or `nil`. Also note that this function will behave like
+// `PutChange`, only make type assertions for fields and no
+// additional validation.
+func (c Changeset[T]) UpdateChange(field string, cb func(interface{}) (interface{}, error)) Changeset[T] {
+	v, err := cb(c.changes[field])
+	if err != nil {
+		c = c.AddError(field, err)
+		c.IsValid = false
+		return c
+	}
+	return c.PutChange(field, v)
+}
+
+// Applies the given function on every string change, like
+// `strings.TrimSpace` for a normalization pass. Changes of
+// other types are kept untouched.
+func (c Changeset[T]) MapStringChanges(fn func(string) string) Changeset[T] {
+	c = c.clone()
+
+	for field, change := range c.changes {
+		if str, ok := change.(string); ok {
+			c.changes[field] = fn(str)
+		}
+	}
+
+	return c
+}
+
+// Interface to define custom validations for changesets.
+// Check `ValidateChange` for more information.
+type Validator interface {
+	Validate(field string, value interface{}) (bool, error)
+}
+
+// Optional interface for validators that need the changeset to
+// validate a field, like reading the change of another field.
+// When a `Validator` also implements it for the same data type,
+// `ValidateChange` calls `ValidateInChangeset` instead of `Validate`.
+type ContextValidator[T interface{}] interface {
+	ValidateInChangeset(c *Changeset[T], field string, value interface{}) (bool, error)
+}
+
+// Optional interface for validators that adapt to the declared
+// type of the field. When a `Validator` also implements it,
+// `ValidateChange` calls `ValidateTyped` instead of `Validate`.
+type TypedValidator interface {
+	ValidateTyped(field string, value interface{}, fieldType reflect.Type) (bool, error)
+}
+
+// Error returned by the built-in validations. Besides the usual
+// English message returned by `Error`, it carries a stable `Key`,
+// like "length.min", and the `Params` the message is built from,
+// so errors can be translated downstream. Use `errors.As` to
+// get it from the changeset errors.
+type ValidationError struct {
+	Key     string
+	Params  map[string]interface{}
+	Message string
+}
+
+func (ve *ValidationError) Error() string {
+	return ve.Message
+}
+
+func newValidationError(key string, params map[string]interface{}, format string, args ...interface{}) error {
+	return &ValidationError{Key: key, Params: params, Message: fmt.Sprintf(format, args...)}
+}
+
+// Error for values of an unexpected type, keyed "type" like
+// the ones of `ValidateSchema`, with the expected type as param.
+func typeError(kind, format string, args ...interface{}) error {
+	return newValidationError("type", map[string]interface{}{"type": kind}, format, args...)
+}
+
+// Adapts a plain function into a `Validator`, for one-off
+// validations that don't deserve their own type, like
+// `c.ValidateChange("Age", changeset.FuncValidator(check))`.
+type FuncValidator func(field string, val interface{}) (bool, error)
+
+func (fv FuncValidator) Validate(field string, val interface{}) (bool, error) {
+	return fv(field, val)
+}
+
+// Validates that a given change has the desired length.
+// It works on string, map and slice types.
+// If you want an **exact** length, give the `Min` and `Max`
+// the same value.
+// Strings are measured in bytes by default, set `CountRunes`
+// to measure them in runes or `CountGraphemes` to measure them
+// in user perceived characters, where an emoji made of many
+// runes counts as one.
+type LengthValidator struct {
+	Min            int
+	Max            int
+	CountRunes     bool
+	CountGraphemes bool
+}
+
+func (lv LengthValidator) Validate(field string, v interface{}) (bool, error) {
+	var l int
+	val := reflect.ValueOf(v)
+	var msg, kind string
+
+	switch val.Kind() {
+	case reflect.String:
+		switch s := val.String(); {
+		case lv.CountGraphemes:
+			l = graphemeCount(s)
+		case lv.CountRunes:
+			l = utf8.RuneCountInString(s)
+		default:
+			l = len(s)
+		}
+		msg, kind = "should be %s %d characters", "characters"
+	case reflect.Slice:
+		l = val.Len()
+		msg, kind = "should have %s %d items", "items"
+	case reflect.Map:
+		l = val.Len()
+		msg, kind = "should have %s %d elements", "elements"
+	default:
+		l = -1
+	}
+
+	if lv.Min == lv.Max && l != lv.Min {
+		params := map[string]interface{}{"count": lv.Min, "kind": kind}
+		return false, newValidationError("length.is", params, msg, "", lv.Min)
+	}
+
+	if l < lv.Min {
+		params := map[string]interface{}{"count": lv.Min, "kind": kind}
+		return false, newValidationError("length.min", params, msg, "at least", lv.Min)
+	}
+
+	if l > lv.Max {
+		params := map[string]interface{}{"count": lv.Max, "kind": kind}
+		return false, newValidationError("length.max", params, msg, "at most", lv.Max)
+	}
+
+	return true, nil
+}
+
+// Counts the grapheme clusters of a string. It approximates the
+// Unicode segmentation rules by keeping combining marks, variation
+// selectors, emoji modifiers, tags, zero width joiner sequences,
+// regional indicator pairs and CRLF on the previous cluster.
+func graphemeCount(s string) int {
+	var count int
+	var joined, pairing bool
+	prev := rune(-1)
+
+	for _, r := range s {
+		regional := r >= 0x1F1E6 && r <= 0x1F1FF
+		extends := joined ||
+			r == 0x200D ||
+			unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+			(r >= 0xFE00 && r <= 0xFE0F) ||
+			(r >= 0x1F3FB && r <= 0x1F3FF) ||
+			(r >= 0xE0020 && r <= 0xE007F) ||
+			(prev == '\r' && r == '\n') ||
+			(regional && pairing)
+
+		if !extends {
+			count++
+		}
+
+		pairing = regional && !pairing
+		joined = r == 0x200D
+		prev = r
+	}
+
+	return count
+}
+
+// Validates that a numeric value fits into the given integer
+// `Kind` without overflow or truncation. Useful when a wider
+// value, like a JSON float64, is meant for a smaller field.
+type IntRangeValidator struct {
+	Kind reflect.Kind
+}
+
+func (irv IntRangeValidator) Validate(field string, val interface{}) (bool, error) {
+	t, ok := intTypes[irv.Kind]
+	if !ok {
+		params := map[string]interface{}{"kind": irv.Kind}
+		return false, newValidationError("number.int_range.kind", params, "%s isn't an integer kind", irv.Kind)
+	}
+
+	if _, err := toInt(val, t); err != nil {
+		params := map[string]interface{}{"kind": irv.Kind}
+		return false, newValidationError("number.int_range", params, "%v", err)
+	}
+
+	return true, nil
+}
+
+// Validates if a string field would match the given Regexp pattern.
+type FormatValidator struct {
+	Pattern *regexp.Regexp
+}
+
+func (fv FormatValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if fv.Pattern.FindString(v) == "" {
+		return false, newValidationError("format", nil, "has invalid format")
+	}
+
+	return true, nil
+}
+
+// Validates if a string field is valid UTF-8, catching
+// binary or mis-encoded input.
+type UTF8Validator struct{}
+
+func (uv UTF8Validator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if !utf8.ValidString(v) {
+		return false, newValidationError("utf8", nil, "contains invalid characters")
+	}
+
+	return true, nil
+}
+
+// Validates that a string field isn't empty nor only made of
+// whitespace. Unlike `ValidateRequired`, a present but blank
+// change fails. Non-string values error unless `AllowNonString`
+// is set, in which case they pass.
+type PresenceValidator struct {
+	AllowNonString bool
+}
+
+func (pv PresenceValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		if pv.AllowNonString {
+			return true, nil
+		}
+		return false, typeError("string", "is not a string")
+	}
+
+	if strings.TrimSpace(v) == "" {
+		return false, newValidationError("blank", nil, "can't be blank")
+	}
+
+	return true, nil
+}
+
+// Validates if a string field is a `#RRGGBB` hex color.
+// `AllowShorthand` also accepts `#RGB` and `AllowAlpha` accepts
+// `#RRGGBBAA`, or `#RGBA` when both are set.
+type HexColorValidator struct {
+	AllowShorthand bool
+	AllowAlpha     bool
+}
+
+func (hv HexColorValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	digits, found := strings.CutPrefix(v, "#")
+	valid := found && strings.Trim(digits, "0123456789abcdefABCDEF") == ""
+
+	switch len(digits) {
+	case 6:
+	case 3:
+		valid = valid && hv.AllowShorthand
+	case 8:
+		valid = valid && hv.AllowAlpha
+	case 4:
+		valid = valid && hv.AllowShorthand && hv.AllowAlpha
+	default:
+		valid = false
+	}
+
+	if !valid {
+		return false, newValidationError("hex_color", nil, "is not a valid hex color")
+	}
+
+	return true, nil
+}
+
+var uuidPattern = regexp.MustCompile(`^(?i)[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// Validates if a string field is an UUID on its canonical
+// 8-4-4-4-12 hex form, case insensitive. A non zero `Version`
+// also pins the UUID version digit.
+type UUIDValidator struct {
+	Version int
+}
+
+func (uv UUIDValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if !uuidPattern.MatchString(v) {
+		return false, newValidationError("uuid", nil, "is not a valid UUID")
+	}
+
+	if uv.Version != 0 && v[14:15] != strconv.FormatInt(int64(uv.Version), 16) {
+		params := map[string]interface{}{"version": uv.Version}
+		return false, newValidationError("uuid.version", params, "is not a version %d UUID", uv.Version)
+	}
+
+	return true, nil
+}
+
+var e164Pattern = regexp.MustCompile(`^\+[0-9]{1,15}$`)
+
+// Validates if a string field is a phone number on the E.164
+// form: a leading "+" followed by 1 to 15 digits, without any
+// formatting characters, like "+14155550123".
+type E164Validator struct{}
+
+func (ev E164Validator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if !e164Pattern.MatchString(v) {
+		return false, newValidationError("e164", nil, "is not a valid E.164 phone number")
+	}
+
+	return true, nil
+}
+
+// Validates if a string field is a cron expression with the
+// standard 5 fields, or 6 with a leading seconds field, or one
+// of the `@yearly`, `@annually`, `@monthly`, `@weekly`, `@daily`,
+// `@midnight` and `@hourly` macros. Fields accept `*`, values,
+// ranges, lists and steps, and months and week days accept
+// their three letters names, like "*/15 9-17 * JAN-JUN MON-FRI".
+type CronValidator struct{}
+
+func (cv CronValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if !isCron(v) {
+		return false, newValidationError("cron", nil, "is not a valid cron expression")
+	}
+
+	return true, nil
+}
+
+type cronField struct {
+	min, max int
+	names    []string
+}
+
+var (
+	cronSeconds = cronField{min: 0, max: 59}
+	cronFields  = []cronField{
+		{min: 0, max: 59},
+		{min: 0, max: 23},
+		{min: 1, max: 31},
+		{min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+		{min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}},
+	}
+	cronMacros = map[string]bool{
+		"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+		"@daily": true, "@midnight": true, "@hourly": true,
+	}
+)
+
+func isCron(expr string) bool {
+	if cronMacros[strings.TrimSpace(expr)] {
+		return true
+	}
+
+	parts := strings.Fields(expr)
+	fields := cronFields
+	if len(parts) == 6 {
+		fields = append([]cronField{cronSeconds}, cronFields...)
+	}
+
+	if len(parts) != len(fields) {
+		return false
+	}
+
+	for i, part := range parts {
+		for _, item := range strings.Split(part, ",") {
+			if !fields[i].valid(item) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Check a single item of a cron field list, like "*", "5",
+// "1-5", "*/10" or "MON-FRI/2".
+func (cf cronField) valid(item string) bool {
+	rng, step, stepped := strings.Cut(item, "/")
+	if stepped {
+		if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+			return false
+		}
+	}
+
+	if rng == "*" {
+		return true
+	}
+
+	lo, hi, ranged := strings.Cut(rng, "-")
+	from, ok := cf.value(lo)
+	if !ok {
+		return false
+	}
+
+	if !ranged {
+		return true
+	}
+
+	to, ok := cf.value(hi)
+	return ok && from <= to
+}
+
+func (cf cronField) value(s string) (int, bool) {
+	for i, name := range cf.names {
+		if strings.EqualFold(s, name) {
+			return cf.min + i, true
+		}
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < cf.min || n > cf.max {
+		return 0, false
+	}
+
+	return n, true
+}
+
+// Validates if a string field is an ISBN-10 or ISBN-13 with a
+// valid check digit. Hyphens and spaces are ignored.
+type ISBNValidator struct{}
+
+func (iv ISBNValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	isbn := strings.NewReplacer("-", "", " ", "").Replace(v)
+
+	sum := 0
+	switch len(isbn) {
+	case 10:
+		for i, r := range isbn {
+			d := int(r - '0')
+			if i == 9 && (r == 'X' || r == 'x') {
+				d = 10
+			} else if r < '0' || r > '9' {
+				return false, newValidationError("isbn", nil, "is not a valid ISBN")
+			}
+			sum += (10 - i) * d
+		}
+		ok = sum%11 == 0
+	case 13:
+		for i, r := range isbn {
+			if r < '0' || r > '9' {
+				return false, newValidationError("isbn", nil, "is not a valid ISBN")
+			}
+			sum += int(r-'0') * (1 + 2*(i%2))
+		}
+		ok = sum%10 == 0
+	default:
+		ok = false
+	}
+
+	if !ok {
+		return false, newValidationError("isbn", nil, "is not a valid ISBN")
+	}
+
+	return true, nil
+}
+
+// Validates if a string field is an IBAN: a country code, two
+// check digits and up to 30 alphanumeric characters, checked with
+// the mod-97 algorithm. Spaces are ignored and letters can be on
+// any case.
+type IBANValidator struct{}
+
+func (iv IBANValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	iban := strings.ToUpper(strings.ReplaceAll(v, " ", ""))
+	if len(iban) < 15 || len(iban) > 34 ||
+		strings.Trim(iban[:2], "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" ||
+		strings.Trim(iban[2:4], "0123456789") != "" {
+		return false, newValidationError("iban", nil, "is not a valid IBAN")
+	}
+
+	rem := 0
+	for _, r := range iban[4:] + iban[:4] {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A') + 10) % 97
+		default:
+			return false, newValidationError("iban", nil, "is not a valid IBAN")
+		}
+	}
+
+	if rem != 1 {
+		return false, newValidationError("iban", nil, "is not a valid IBAN")
+	}
+
+	return true, nil
+}
+
+// Validates if a string field is an EUI-48 or EUI-64 MAC
+// address, with colon or hyphen separated octets.
+type MACValidator struct{}
+
+func (mv MACValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	hw, err := net.ParseMAC(v)
+	if err != nil || !strings.ContainsAny(v, ":-") || (len(hw) != 6 && len(hw) != 8) {
+		return false, newValidationError("mac", nil, "is not a valid MAC address")
+	}
+
+	return true, nil
+}
+
+// Selects the list of codes a `CodeValidator` checks against.
+type CodeSet int
+
+const (
+	// ISO 3166-1 alpha-2 country codes, like "US" or "BR".
+	CountryCodes CodeSet = iota
+	// ISO 4217 currency codes, like "USD" or "BRL".
+	CurrencyCodes
+)
+
+// Validates if a string field is a code of the given `CodeSet`.
+// Codes are matched case sensitively, in upper case.
+type CodeValidator struct {
+	Set CodeSet
+}
+
+func (cv CodeValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	codes := countryCodes
+	if cv.Set == CurrencyCodes {
+		codes = currencyCodes
+	}
+
+	if _, ok := codes[v]; !ok {
+		params := map[string]interface{}{"set": cv.Set}
+		return false, newValidationError("code", params, "is not a valid code")
+	}
+
+	return true, nil
+}
+
+// Validates that a map change has at most `Max` entries,
+// protecting against unbounded metadata maps.
+type MaxEntriesValidator struct {
+	Max int
+}
+
+func (mv MaxEntriesValidator) Validate(field string, val interface{}) (bool, error) {
+	v := reflect.ValueOf(val)
+
+	if v.Kind() != reflect.Map {
+		return false, typeError("map", "is not a map")
+	}
+
+	if v.Len() > mv.Max {
+		params := map[string]interface{}{"max": mv.Max}
+		return false, newValidationError("max_entries", params, "has too many entries")
+	}
+
+	return true, nil
+}
+
+// Validates that a `time.Time` field is within `Min` and `Max`,
+// inclusive, like a booking date. A zero `Min` or `Max` leaves
+// that side of the range unbounded.
+type DateRangeValidator struct {
+	Min time.Time
+	Max time.Time
+}
+
+func (dv DateRangeValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(time.Time)
+
+	if !ok {
+		return false, typeError("time", "is not a time")
+	}
+
+	if !dv.Min.IsZero() && v.Before(dv.Min) {
+		params := map[string]interface{}{"min": dv.Min}
+		return false, newValidationError("date_range.min", params, "must be on or after %s", dv.Min.Format(time.RFC3339))
+	}
+
+	if !dv.Max.IsZero() && v.After(dv.Max) {
+		params := map[string]interface{}{"max": dv.Max}
+		return false, newValidationError("date_range.max", params, "must be on or before %s", dv.Max.Format(time.RFC3339))
+	}
+
+	return true, nil
+}
+
+// Validates that a date of birth string field, parsed with
+// `Layout` (defaults to "2006-01-02"), is at least `MinYears`
+// years ago as of `Now` (defaults to `time.Now`).
+type AgeValidator struct {
+	MinYears int
+	Layout   string
+	Now      func() time.Time
+}
+
+func (av AgeValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	layout := av.Layout
+	if layout == "" {
+		layout = time.DateOnly
+	}
+
+	dob, err := time.Parse(layout, v)
+	if err != nil {
+		params := map[string]interface{}{"layout": layout}
+		return false, newValidationError("date", params, "is not a valid date")
+	}
+
+	now := time.Now()
+	if av.Now != nil {
+		now = av.Now()
+	}
+
+	age := now.Year() - dob.Year()
+	if now.Month() < dob.Month() || (now.Month() == dob.Month() && now.Day() < dob.Day()) {
+		age--
+	}
+
+	if age < av.MinYears {
+		params := map[string]interface{}{"min_years": av.MinYears}
+		return false, newValidationError("age", params, "must be at least %d years old", av.MinYears)
+	}
+
+	return true, nil
+}
+
+// Validates that a slice change has a multiple of `Divisor`
+// items, like coordinates that must come in pairs.
+type LengthMultipleOfValidator struct {
+	Divisor int
+}
+
+func (lv LengthMultipleOfValidator) Validate(field string, val interface{}) (bool, error) {
+	v := reflect.ValueOf(val)
+
+	if v.Kind() != reflect.Slice {
+		return false, typeError("slice", "is not a slice")
+	}
+
+	if lv.Divisor == 0 || v.Len()%lv.Divisor != 0 {
+		params := map[string]interface{}{"divisor": lv.Divisor}
+		return false, newValidationError("length.multiple_of", params, "must have a multiple of %d items", lv.Divisor)
+	}
+
+	return true, nil
+}
+
+// Validates that a string field has between `Min` and `Max`
+// words, splitting it on whitespace.
+type WordCountValidator struct {
+	Min int
+	Max int
+}
+
+func (wv WordCountValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if n := len(strings.Fields(v)); n < wv.Min || n > wv.Max {
+		params := map[string]interface{}{"min": wv.Min, "max": wv.Max}
+		return false, newValidationError("word_count", params, "must have between %d and %d words", wv.Min, wv.Max)
+	}
+
+	return true, nil
+}
+
+// Validates if a string field is a date in the given time layout.
+// The layout can depend on the changeset, like on another field
+// change, by setting `LayoutFunc`, which takes precedence over
+// `Layout` when validating through `ValidateChange`.
+type DateValidator[T interface{}] struct {
+	Layout     string
+	LayoutFunc func(*Changeset[T]) string
+}
+
+func (dv DateValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	return dv.validate(dv.Layout, val)
+}
+
+func (dv DateValidator[T]) ValidateInChangeset(c *Changeset[T], field string, val interface{}) (bool, error) {
+	layout := dv.Layout
+	if dv.LayoutFunc != nil {
+		layout = dv.LayoutFunc(c)
+	}
+
+	return dv.validate(layout, val)
+}
+
+func (dv DateValidator[T]) validate(layout string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if _, err := time.Parse(layout, v); err != nil {
+		params := map[string]interface{}{"layout": layout}
+		return false, newValidationError("date", params, "is not a valid date")
+	}
+
+	return true, nil
+}
+
+// Validates that a string field is a file path whose extension,
+// case insensitively, is one of `AllowedExtensions`, given with or
+// without the leading dot. Any extension is allowed when empty.
+// Set `DisallowTraversal` to reject paths containing "..".
+type FilePathValidator struct {
+	AllowedExtensions []string
+	DisallowTraversal bool
+}
+
+func (fv FilePathValidator) Validate(field string, val interface{}) (bool, error) {
+	v, ok := val.(string)
+
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	if fv.DisallowTraversal && strings.Contains(v, "..") {
+		return false, newValidationError("file_path.traversal", nil, "must not traverse directories")
+	}
+
+	if len(fv.AllowedExtensions) == 0 {
+		return true, nil
+	}
+
+	ext := strings.TrimPrefix(filepath.Ext(v), ".")
+	for _, allowed := range fv.AllowedExtensions {
+		if ext != "" && strings.EqualFold(ext, strings.TrimPrefix(allowed, ".")) {
+			return true, nil
+		}
+	}
+
+	params := map[string]interface{}{"allowed": fv.AllowedExtensions}
+	return false, newValidationError("file_path.extension", params, "has an invalid extension")
+}
+
+// Validates that every element of a slice change satisfies
+// `Predicate`, failing with `Message` (defaults to "is invalid")
+// and the index of the first offending element.
+type AllValidator struct {
+	Predicate func(interface{}) bool
+	Message   string
+}
+
+func (av AllValidator) Validate(field string, val interface{}) (bool, error) {
+	v := reflect.ValueOf(val)
+
+	if v.Kind() != reflect.Slice {
+		return false, typeError("slice", "is not a slice")
+	}
+
+	msg := av.Message
+	if msg == "" {
+		msg = "is invalid"
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if !av.Predicate(v.Index(i).Interface()) {
+			params := map[string]interface{}{"message": msg, "index": i}
+			return false, newValidationError("all", params, "%s at index %d", msg, i)
+		}
+	}
+
+	return true, nil
+}
+
+// Validates that a string field isn't blank after trimming its
+// whitespace, without pinning any length. Non-string values
+// error. It's the same as a `PresenceValidator` without options.
+type NotBlankValidator struct{}
+
+func (nv NotBlankValidator) Validate(field string, val interface{}) (bool, error) {
+	return PresenceValidator{}.Validate(field, val)
+}
+
+// Validates that a string, like a token or a password, carries
+// at least `MinBits` of Shannon entropy, estimated from its
+// runes frequencies times its length. Non-string values error.
+type EntropyValidator struct {
+	MinBits float64
+}
+
+func (ev EntropyValidator) Validate(field string, val interface{}) (bool, error) {
+	str, ok := val.(string)
+	if !ok {
+		return false, typeError("string", "is not a string")
+	}
+
+	freq := make(map[rune]int)
+	n := 0
+	for _, r := range str {
+		freq[r]++
+		n++
+	}
+
+	var bits float64
+	for _, count := range freq {
+		p := float64(count) / float64(n)
+		bits -= p * math.Log2(p)
+	}
+
+	if bits*float64(n) < ev.MinBits {
+		params := map[string]interface{}{"min_bits": ev.MinBits}
+		return false, newValidationError("entropy", params, "is too predictable")
+	}
+
+	return true, nil
+}
+
+// Validates if a boolean field is true.
+type AcceptanceValidator struct{}
+
+func (av AcceptanceValidator) Validate(field string, val interface{}) (bool, error) {
+	accepted, ok := val.(bool)
+
+	if !ok {
+		return false, typeError("boolean", "isn't a boolean")
+	}
+
+	if !accepted {
+		return false, newValidationError("acceptance", nil, "must be accepted")
+	}
+
+	return true, nil
+}
+
+// Given a list of disallowed values, validates if the
+// value of a field isn't included into this list.
+type ExclusionValidator struct {
+	Disallowed []interface{}
+}
+
+func (ev ExclusionValidator) Validate(field string, value interface{}) (bool, error) {
+	for _, disallowed := range ev.Disallowed {
+		if reflect.DeepEqual(value, disallowed) {
+			return false, newValidationError("exclusion", nil, "is reserved")
+		}
+	}
+
+	return true, nil
+}
+
+// Given a slice of desired values, validates if the
+// value of a field is included on this slice.
+// It can act like a type of "Enum", listing the allowed
+// values on its error message.
+// Set `NumericAware` to compare numbers regardless of their
+// types, so a JSON decoded float64(2) matches an allowed int 2.
+type InclusionValidator struct {
+	Allowed      []interface{}
+	NumericAware bool
+}
+
+func (iv InclusionValidator) Validate(field string, value interface{}) (bool, error) {
+	n, numeric := toFloat(value)
+
+	for _, allowed := range iv.Allowed {
+		if reflect.DeepEqual(value, allowed) {
+			return true, nil
+		}
+
+		if a, ok := toFloat(allowed); iv.NumericAware && numeric && ok && n == a {
+			return true, nil
+		}
+	}
+
+	allowed := make([]string, len(iv.Allowed))
+	for i, a := range iv.Allowed {
+		allowed[i] = fmt.Sprint(a)
+	}
+
+	params := map[string]interface{}{"allowed": iv.Allowed}
+	return false, newValidationError("inclusion", params, "must be one of: %s", strings.Join(allowed, ", "))
+}
+
+// Same as InclusionValidator but the allowed values are computed
+// at validation time from the changeset, like from another field
+// change. The set is only known through `ValidateChange`, so
+// validating outside a changeset always fails.
+type InSetFunc[T interface{}] struct {
+	Allowed func(*Changeset[T]) []interface{}
+}
+
+func (iv InSetFunc[T]) Validate(field string, value interface{}) (bool, error) {
+	return InclusionValidator{}.Validate(field, value)
+}
+
+func (iv InSetFunc[T]) ValidateInChangeset(c *Changeset[T], field string, value interface{}) (bool, error) {
+	return InclusionValidator{Allowed: iv.Allowed(c)}.Validate(field, value)
+}
+
+// Given a slice of desired values, validates if every
+// element of a slice change is included on this slice.
+// It complements `InclusionValidator` for list fields,
+// like a list of roles.
+type SubsetValidator struct {
+	Allowed []interface{}
+}
+
+func (sv SubsetValidator) Validate(field string, value interface{}) (bool, error) {
+	v := reflect.ValueOf(value)
+
+	if v.Kind() != reflect.Slice {
+		return false, typeError("slice", "is not a slice")
+	}
+
+	inclusion := InclusionValidator{Allowed: sv.Allowed}
+	for i := 0; i < v.Len(); i++ {
+		if ok, _ := inclusion.Validate(field, v.Index(i).Interface()); !ok {
+			params := map[string]interface{}{"allowed": sv.Allowed}
+			return false, newValidationError("subset", params, "has an invalid entry")
+		}
+	}
+
+	return true, nil
+}
+
+// Validates that a slice change includes an element deep
+// equal to `Element`, like roles that must include "member".
+type ContainsValidator struct {
+	Element interface{}
+}
+
+func (cv ContainsValidator) Validate(field string, value interface{}) (bool, error) {
+	v := reflect.ValueOf(value)
+
+	if v.Kind() != reflect.Slice {
+		return false, typeError("slice", "is not a slice")
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		if reflect.DeepEqual(v.Index(i).Interface(), cv.Element) {
+			return true, nil
+		}
+	}
+
+	params := map[string]interface{}{"element": cv.Element}
+	return false, newValidationError("contains", params, "must include the required value")
+}
+
+// Interface to define a possible numeric value.
+type Number interface {
+	int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64 | float32 | float64
+}
+
+// Interface to define a possible integer value.
+type Integer interface {
+	int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64
+}
+
+// Validates that an `Integer` is even, or odd when `Even` is false.
+// Changes of other integer types are accepted when they convert
+// without loss, while floats always error.
+type ParityValidator[T Integer] struct {
+	Even bool
+}
+
+func (pv ParityValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toInteger[T](val)
+
+	if !ok {
+		return false, typeError("integer", "isn't an Integer %v", val)
+	}
+
+	if even := v%2 == 0; even != pv.Even {
+		if pv.Even {
+			return false, newValidationError("parity.even", nil, "must be even")
+		}
+		return false, newValidationError("parity.odd", nil, "must be odd")
+	}
+
+	return true, nil
+}
+
+// Validates that a `Number` is less than a given a max value.
+type LessThanValidator[T Number] struct {
+	MaxValue T
+}
+
+func (ltv LessThanValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v > ltv.MaxValue {
+		params := map[string]interface{}{"value": v, "max": ltv.MaxValue}
+		return false, newValidationError("number.less_than", params, "must be less than %v", v)
+	}
+
+	return true, nil
+}
+
+// Validates if a `Number` field is less than or equal to a max value.
+type LessThanOrEqualValidator[T Number] struct {
+	MaxValue T
+}
+
+func (ltv LessThanOrEqualValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v >= ltv.MaxValue {
+		params := map[string]interface{}{"value": v, "max": ltv.MaxValue}
+		return false, newValidationError("number.less_than_or_equal_to", params, "must be less than or equal to %v", v)
+	}
+
+	return true, nil
+}
+
+// Validates that a `Number` field is greater than a given minimal value.
+type GreaterThanValidator[T Number] struct {
+	MinValue T
+}
+
+func (gtv GreaterThanValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v < gtv.MinValue {
+		params := map[string]interface{}{"value": v, "min": gtv.MinValue}
+		return false, newValidationError("number.greater_than", params, "must be greater than %v", v)
+	}
+
+	return true, nil
+}
+
+// Validates if a `Number` field is greater than or equal to a given minimal value.
+type GreaterThanOrEqualValidator[T Number] struct {
+	MinValue T
+}
+
+func (gtv GreaterThanOrEqualValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v <= gtv.MinValue {
+		params := map[string]interface{}{"value": v, "min": gtv.MinValue}
+		return false, newValidationError("number.greater_than_or_equal_to", params, "must be greater than or equal to %v", v)
+	}
+
+	return true, nil
+}
+
+// Validates if a `Number` value is equal to a given exact value.
+type EqualToValidator[T Number] struct {
+	Value T
+}
+
+func (ev EqualToValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v == ev.Value {
+		return true, nil
+	}
+
+	params := map[string]interface{}{"value": v, "expected": ev.Value}
+	return false, newValidationError("number.equal_to", params, "must be equal to %v", v)
+}
+
+// Validates if a `Number` value is different to a given exact value.
+type NotEqualToValidator[T Number] struct {
+	Value T
+}
+
+func (nev NotEqualToValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v != nev.Value {
+		return true, nil
+	}
+
+	params := map[string]interface{}{"value": v, "unexpected": nev.Value}
+	return false, newValidationError("number.not_equal_to", params, "must be not equal to %v", v)
+}
+
+// Validates that an `Integer` only has bits set within `Mask`,
+// like permission bitfields rejecting unknown flags. Changes of
+// other integer types are accepted when they convert without loss,
+// while floats always error.
+type FlagsValidator[T Integer] struct {
+	Mask T
+}
+
+func (fv FlagsValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toInteger[T](val)
+
+	if !ok {
+		return false, typeError("integer", "isn't an Integer %v", val)
+	}
+
+	if v&^fv.Mask != 0 {
+		params := map[string]interface{}{"mask": fv.Mask}
+		return false, newValidationError("flags", params, "contains unknown flags")
+	}
+
+	return true, nil
+}
+
+// Validates that a `Number` is between `Min` and `Max`, inclusive,
+// in a single validation.
+type RangeValidator[T Number] struct {
+	Min T
+	Max T
+}
+
+func (rv RangeValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v < rv.Min || v > rv.Max {
+		params := map[string]interface{}{"min": rv.Min, "max": rv.Max}
+		return false, newValidationError("number.range", params, "must be between %v and %v", rv.Min, rv.Max)
+	}
+
+	return true, nil
+}
+
+// Validates that a `Number` is a multiple of `Base`, like order
+// quantities sold in packs. Floats are compared with a small
+// tolerance and a zero `Base` always errors.
+type MultipleOfValidator[T Number] struct {
+	Base T
+}
+
+func (mv MultipleOfValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if mv.Base == 0 {
+		return false, newValidationError("number.multiple_of.zero", nil, "base must not be zero")
+	}
+
+	multiple := math.Mod(float64(v), float64(mv.Base)) == 0
+	if k := reflect.TypeOf(v).Kind(); k == reflect.Float32 || k == reflect.Float64 {
+		q := float64(v) / float64(mv.Base)
+		multiple = math.Abs(q-math.Round(q)) <= 1e-9
+	}
+
+	if !multiple {
+		params := map[string]interface{}{"value": v, "base": mv.Base}
+		return false, newValidationError("number.multiple_of", params, "must be a multiple of %v", mv.Base)
+	}
+
+	return true, nil
+}
+
+// Validates that a `Number` aligns to a grid starting at `Start`
+// with increments of `Step`, like sliders and steppers values.
+// Floats are compared with a small tolerance.
+type StepValidator[T Number] struct {
+	Start T
+	Step  T
+}
+
+func (sv StepValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if sv.Step == 0 {
+		return false, newValidationError("number.step.zero", nil, "step must not be zero")
+	}
+
+	q := (float64(v) - float64(sv.Start)) / float64(sv.Step)
+	if math.Abs(q-math.Round(q)) > 1e-9 {
+		params := map[string]interface{}{"start": sv.Start, "step": sv.Step}
+		return false, newValidationError("number.step", params, "must align to step")
+	}
+
+	return true, nil
+}
+
+// Validates that a float field is a probability, between 0
+// and 1 inclusive. Values of other types error.
+type ProbabilityValidator struct{}
+
+func (pv ProbabilityValidator) Validate(field string, val interface{}) (bool, error) {
+	var v float64
+	switch f := val.(type) {
+	case float32:
+		v = float64(f)
+	case float64:
+		v = f
+	default:
+		return false, typeError("float", "isn't a Float %v", val)
+	}
+
+	if v < 0 || v > 1 {
+		params := map[string]interface{}{"value": v, "min": 0, "max": 1}
+		return false, newValidationError("number.probability", params, "must be between 0 and 1")
+	}
+
+	return true, nil
+}
+
+// Validates that a `Number` is a percentage, between 0 and 100.
+// Unless `AllowFractional` is set, the value must also be whole.
+type PercentageValidator[T Number] struct {
+	AllowFractional bool
+}
+
+func (pv PercentageValidator[T]) Validate(field string, val interface{}) (bool, error) {
+	v, ok := toNumber[T](val)
+
+	if !ok {
+		return false, typeError("number", "isn't a Number %v", val)
+	}
+
+	if v < 0 || v > 100 {
+		params := map[string]interface{}{"value": v, "min": 0, "max": 100}
+		return false, newValidationError("number.percentage", params, "must be between 0 and 100")
+	}
+
+	if f := float64(v); !pv.AllowFractional && f != math.Trunc(f) {
+		return false, newValidationError("number.whole", nil, "must be a whole number")
+	}
+
+	return true, nil
+}
+
+// Given a slice of fields names, validates if all of them
+// are present on the `changes` Changeset field, ensuring
+// their existence.
+func (c Changeset[T]) ValidateRequired(need []string) Changeset[T] {
+	c = c.clone()
+
+	for _, field := range need {
+		fieldValue, exists := c.changes[field]
+
+		if !exists || !reflect.ValueOf(fieldValue).IsValid() {
+			c.IsValid = false
+			c.errors[field] = append(c.errors[field], newValidationError("required", nil, "is required"))
+		}
+	}
+
+	return c
+}
+
+// Same as ValidateRequired for a single field, but only when
+// the change of another field deep equals the given value.
+// For example, requiring a state only when the country is "US".
+func (c Changeset[T]) ValidateRequiredWhenEquals(field, otherField string, value interface{}) Changeset[T] {
+	other, exists := c.changes[otherField]
+
+	if !exists || !reflect.DeepEqual(other, value) {
+		return c
+	}
+
+	return c.ValidateRequired([]string{field})
+}
+
+// Validates that the change of a field and of its confirmation
+// field, like a password and its confirmation, are deep equal.
+// On mismatch, including a missing confirmation, the error is
+// added to the confirmation field.
+func (c Changeset[T]) ValidateConfirmation(field, confirmationField string) Changeset[T] {
+	c = c.clone()
+
+	change := c.changes[field]
+	confirmation, exists := c.changes[confirmationField]
+
+	if !exists || !reflect.DeepEqual(change, confirmation) {
+		params := map[string]interface{}{"field": field}
+		c.errors[confirmationField] = append(c.errors[confirmationField], newValidationError("confirmation", params, "does not match"))
+		c.IsValid = false
+	}
+
+	return c
+}
+
+// Validates that the numeric changes of the given fields sum up
+// to the target, within a small tolerance. Missing changes count
+// as zero. A wrong sum is added as an error on the `BaseKey`,
+// while non numeric changes are added as errors on their fields.
+func (c Changeset[T]) ValidateSumEquals(fields []string, target float64) Changeset[T] {
+	c = c.clone()
+
+	var sum float64
+	numeric := true
+
+	for _, field := range fields {
+		change, exists := c.changes[field]
+		if !exists {
+			continue
+		}
+
+		n, ok := toFloat(change)
+		if !ok {
+			numeric = false
+			c.errors[field] = append(c.errors[field], typeError("number", "isn't a Number %v", change))
+			c.IsValid = false
+			continue
+		}
+
+		sum += n
+	}
+
+	if numeric && math.Abs(sum-target) > 1e-9 {
+		params := map[string]interface{}{"fields": fields, "target": target}
+		c.errors[BaseKey] = append(c.errors[BaseKey], newValidationError("sum", params, "%s must sum up to %v", strings.Join(fields, ", "), target))
+		c.IsValid = false
+	}
+
+	return c
+}
+
+// Validates that the numeric change of a field is within a
+// fraction of the change of a reference field, like an amount
+// within 5% (0.05) of an estimate. A zero reference only accepts
+// zero. Missing changes are skipped.
+func (c Changeset[T]) ValidateWithinTolerance(field, referenceField string, fraction float64) Changeset[T] {
+	c = c.clone()
+
+	change, exists := c.changes[field]
+	reference, refExists := c.changes[referenceField]
+	if !exists || !refExists {
+		return c
+	}
+
+	n, ok := toFloat(change)
+	if !ok {
+		c.errors[field] = append(c.errors[field], typeError("number", "isn't a Number %v", change))
+		c.IsValid = false
+		return c
+	}
+
+	ref, ok := toFloat(reference)
+	if !ok {
+		c.errors[referenceField] = append(c.errors[referenceField], typeError("number", "isn't a Number %v", reference))
+		c.IsValid = false
+		return c
+	}
+
+	diff := math.Abs(n - ref)
+	if (ref == 0 && diff != 0) || (ref != 0 && diff/math.Abs(ref) > fraction) {
+		params := map[string]interface{}{"fraction": fraction, "field": referenceField}
+		c.errors[field] = append(c.errors[field], newValidationError("tolerance", params, "must be within %v%% of %s", fraction*100, referenceField))
+		c.IsValid = false
+	}
+
+	return c
+}
+
+// Validates that the numeric change of a field is strictly
+// greater than its current value on the data, like version
+// numbers that must only go up. Seed the data with `CastFrom`.
+func (c Changeset[T]) ValidateIncreasing(field string) Changeset[T] {
+	c = c.clone()
+
+	change, exists := c.changes[field]
+	if !exists {
+		return c
 	}
 
-	if lv.Min == lv.Max && l != lv.Min {
-		return false, fmt.Errorf(msg, "", lv.Min)
+	curr := exo.FieldByName(reflect.ValueOf(c.data), field)
+	if !curr.IsValid() {
+		c.errors[field] = append(c.errors[field], newValidationError("invalid", nil, "%s is invalid", field))
+		c.IsValid = false
+		return c
 	}
 
-	if l < lv.Min {
-		return false, fmt.Errorf(msg, "at least", lv.Min)
+	next, ok := toFloat(change)
+	prev, okPrev := toFloat(curr.Interface())
+	if !ok || !okPrev {
+		c.errors[field] = append(c.errors[field], typeError("number", "isn't a Number %v", change))
+		c.IsValid = false
+		return c
 	}
 
-	if l > lv.Max {
-		return false, fmt.Errorf(msg, "at most", lv.Max)
+	if next <= prev {
+		params := map[string]interface{}{"previous": prev}
+		c.errors[field] = append(c.errors[field], newValidationError("increasing", params, "must increase"))
+		c.IsValid = false
 	}
 
-	return true, nil
+	return c
 }
 
-// Validates if a string field would match the given Regexp pattern.
-type FormatValidator struct {
-	Pattern *regexp.Regexp
-}
+// Given a field and a instance of a `Validator`, apply the
+// validation on the changeset and if any error is present,
+// add it to the `errors` Changeset field, marking it as invalid.
+func (c Changeset[T]) ValidateChange(field string, v Validator) Changeset[T] {
+	c = c.clone()
 
-func (fv FormatValidator) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(string)
+	val, ok := c.GetChange(field)
+	c.validations[field] = v
+	c.validators[field] = append(c.validators[field], v)
 
 	if !ok {
-		return false, fmt.Errorf("is not a string")
+		c.errors[field] = append(c.errors[field], newValidationError("missing", nil, "doesn't exist"))
+		c.IsValid = false
+		return c
 	}
 
-	if fv.Pattern.FindString(v) == "" {
-		return false, fmt.Errorf("has invalid format")
+	if ok, error := c.validate(field, v, val); !ok {
+		c.errors[field] = append(c.errors[field], error)
+		c.IsValid = false
+		return c
 	}
 
-	return true, nil
+	return c
 }
 
-// Validates if a boolean field is true.
-type AcceptanceValidator struct{}
-
-func (av AcceptanceValidator) Validate(field string, val interface{}) (bool, error) {
-	accepted, ok := val.(bool)
+// Runs the validator on the given value, through its changeset
+// aware or typed path when it implements one.
+func (c Changeset[T]) validate(field string, v Validator, val interface{}) (bool, error) {
+	if cv, isContext := v.(ContextValidator[T]); isContext {
+		return cv.ValidateInChangeset(&c, field, val)
+	}
 
-	if !ok {
-		return false, fmt.Errorf("isn't a boolean")
+	if tv, isTyped := v.(TypedValidator); isTyped {
+		if sf, found := reflect.TypeOf(c.data).FieldByName(field); found {
+			return tv.ValidateTyped(field, val, sf.Type)
+		}
 	}
 
-	if !accepted {
-		return false, fmt.Errorf("must be accepted")
+	return v.Validate(field, val)
+}
+
+// Same as ValidateChange but only when the condition holds for
+// the changeset, leaving it untouched otherwise.
+func (c Changeset[T]) ValidateChangeIf(cond func(*Changeset[T]) bool, field string, v Validator) Changeset[T] {
+	if !cond(&c) {
+		return c
 	}
 
-	return true, nil
+	return c.ValidateChange(field, v)
 }
 
-// Given a list of disallowed values, validates if the
-// value of a field isn't included into this list.
-type ExclusionValidator struct {
-	Disallowed []interface{}
+// Threads the changeset through the given steps, in order,
+// so validation pipelines can be built and reused as slices
+// of functions instead of long method chains.
+func Pipe[T interface{}](c Changeset[T], steps ...func(Changeset[T]) Changeset[T]) Changeset[T] {
+	for _, step := range steps {
+		c = step(c)
+	}
+
+	return c
 }
 
-func (ev ExclusionValidator) Validate(field string, value interface{}) (bool, error) {
-	for _, disallowed := range ev.Disallowed {
-		if !reflect.DeepEqual(value, disallowed) {
-			return true, nil
+// Same as ValidateChange but applies the `Validator` to each
+// element of a slice change, like a list of tags. Errors are
+// added under the field name suffixed by the element index,
+// like "Tags.0". Non slice changes add a single error. The
+// validator isn't recorded for the field, as it doesn't apply
+// to the slice as a whole.
+func (c Changeset[T]) ValidateEach(field string, v Validator) Changeset[T] {
+	c = c.clone()
+
+	val, ok := c.GetChange(field)
+	if !ok {
+		c.errors[field] = append(c.errors[field], newValidationError("missing", nil, "doesn't exist"))
+		c.IsValid = false
+		return c
+	}
+
+	s := reflect.ValueOf(val)
+	if s.Kind() != reflect.Slice {
+		c.errors[field] = append(c.errors[field], typeError("slice", "is not a slice"))
+		c.IsValid = false
+		return c
+	}
+
+	for i := 0; i < s.Len(); i++ {
+		key := fmt.Sprintf("%s.%d", field, i)
+		if ok, err := c.validate(key, v, s.Index(i).Interface()); !ok {
+			c.errors[key] = append(c.errors[key], err)
+			c.IsValid = false
 		}
 	}
 
-	return false, fmt.Errorf("is reserved")
+	return c
 }
 
-// Given a slice of desired values, validates if the
-// value of a field is included on this slice.
-// It can act like a type of "Enum".
-type InclusionValidator struct {
-	Allowed []interface{}
+// Same as ValidateChange but applies the same `Validator`
+// to each of the given fields.
+func (c Changeset[T]) ValidateChanges(fields []string, v Validator) Changeset[T] {
+	for _, field := range fields {
+		c = c.ValidateChange(field, v)
+	}
+
+	return c
 }
 
-func (iv InclusionValidator) Validate(field string, value interface{}) (bool, error) {
-	for _, allowed := range iv.Allowed {
-		if reflect.DeepEqual(value, allowed) {
-			return true, nil
-		}
-	}
+// The key used for errors that don't belong to a single field,
+// like the ones added by `ValidateSumEquals`.
+const BaseKey = "base"
 
-	return false, errors.New("is invalid")
+// A field and the error found on it, as reported
+// by a `ChangesetValidator`.
+type FieldError struct {
+	Field string
+	Err   error
 }
 
-// Interface to define a possible numeric value.
-type Number interface {
-	int | uint | int8 | uint8 | int16 | uint16 | int32 | uint32 | int64 | uint64 | float32 | float64
+func (fe FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", fe.Field, fe.Err)
 }
 
-// Validates that a `Number` is less than a given a max value.
-type LessThanValidator[T Number] struct {
-	MaxValue T
+func (fe FieldError) Unwrap() error {
+	return fe.Err
 }
 
-func (ltv LessThanValidator[T]) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(T)
+// Interface to define validations that need the whole
+// changeset, like rules spanning many fields.
+// Check `ValidateWith` for more information.
+type ChangesetValidator[T interface{}] interface {
+	ValidateChangeset(c *Changeset[T]) []FieldError
+}
 
-	if !ok {
-		return false, fmt.Errorf("isn't a Number %v", v)
-	}
+// Given an instance of a `ChangesetValidator`, apply it on the
+// changeset and add every returned field error to the `errors`
+// Changeset field, marking it as invalid.
+// This is the escape hatch for cross-field validations.
+func (c Changeset[T]) ValidateWith(v ChangesetValidator[T]) Changeset[T] {
+	c = c.clone()
 
-	if v > ltv.MaxValue {
-		return false, fmt.Errorf("must be less than %v", v)
+	for _, fe := range v.ValidateChangeset(&c) {
+		c.errors[fe.Field] = append(c.errors[fe.Field], fe.Err)
+		c.IsValid = false
 	}
 
-	return true, nil
+	return c
 }
 
-// Validates if a `Number` field is less than or equal to a max value.
-type LessThanOrEqualValidator[T Number] struct {
-	MaxValue T
+// A serializable set of validation rules for a single field,
+// allowing validations to live in JSON files or databases.
+// Each rule is translated into the equivalent built-in validation.
+type Rule struct {
+	Required  bool          `json:"required,omitempty"`
+	MinLength *int          `json:"min_length,omitempty"`
+	MaxLength *int          `json:"max_length,omitempty"`
+	Pattern   string        `json:"pattern,omitempty"`
+	Accepted  bool          `json:"accepted,omitempty"`
+	OneOf     []interface{} `json:"one_of,omitempty"`
+	NoneOf    []interface{} `json:"none_of,omitempty"`
 }
 
-func (ltv LessThanOrEqualValidator[T]) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(T)
+// Maps fields names to their validation `Rule`.
+type RulesConfig map[string]Rule
 
-	if !ok {
-		return false, fmt.Errorf("isn't a Number %v", v)
+// Given a `RulesConfig`, translate each field `Rule` into the
+// built-in validators and run them on the changeset.
+// Rules other than `Required` are skipped for missing changes
+// and an invalid `Pattern` is added as an error on its field.
+func ApplyRules[T interface{}](c Changeset[T], rules RulesConfig) Changeset[T] {
+	c = c.clone()
+
+	fields := make([]string, 0, len(rules))
+	for field := range rules {
+		fields = append(fields, field)
 	}
+	sort.Strings(fields)
 
-	if v >= ltv.MaxValue {
-		return false, fmt.Errorf("must be less than or equal to %v", v)
+	for _, field := range fields {
+		rule := rules[field]
+
+		if rule.Required {
+			c = c.ValidateRequired([]string{field})
+		}
+
+		if c.IsFieldMissing(field) {
+			continue
+		}
+
+		if rule.MinLength != nil || rule.MaxLength != nil {
+			lv := LengthValidator{Max: math.MaxInt}
+			if rule.MinLength != nil {
+				lv.Min = *rule.MinLength
+			}
+			if rule.MaxLength != nil {
+				lv.Max = *rule.MaxLength
+			}
+			c = c.ValidateChange(field, lv)
+		}
+
+		if rule.Pattern != "" {
+			re, err := regexp.Compile(rule.Pattern)
+			if err != nil {
+				params := map[string]interface{}{"pattern": rule.Pattern}
+				c.errors[field] = append(c.errors[field], newValidationError("rule.pattern", params, "has an invalid pattern rule: %v", err))
+				c.IsValid = false
+			} else {
+				c = c.ValidateChange(field, FormatValidator{Pattern: re})
+			}
+		}
+
+		if rule.Accepted {
+			c = c.ValidateChange(field, AcceptanceValidator{})
+		}
+
+		if rule.OneOf != nil {
+			c = c.ValidateChange(field, InclusionValidator{Allowed: rule.OneOf})
+		}
+
+		if rule.NoneOf != nil {
+			c = c.ValidateChange(field, ExclusionValidator{Disallowed: rule.NoneOf})
+		}
 	}
 
-	return true, nil
+	return c
 }
 
-// Validates that a `Number` field is greater than a given minimal value.
-type GreaterThanValidator[T Number] struct {
-	MinValue T
+// Get the value of a `changes` entry.
+func (c Changeset[T]) GetChange(field string) (interface{}, bool) {
+	v, ok := c.changes[field]
+
+	return v, ok
 }
 
-func (gtv GreaterThanValidator[T]) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(T)
+// Return the slice change of the given field typed as `[]E`, like
+// a validated list of nested structs. Return false when there's
+// no change for the field or it isn't a `[]E`.
+func GetEmbedSlice[E interface{}, T interface{}](c Changeset[T], field string) ([]E, bool) {
+	v, ok := c.changes[field].([]E)
 
-	if !ok {
-		return false, fmt.Errorf("isn't a Number %v", v)
-	}
+	return v, ok
+}
 
-	if v < gtv.MinValue {
-		return false, fmt.Errorf("must be greater than %v", v)
+// Return all current changes that may be applied to the Changeset.
+func (c Changeset[T]) GetChanges() map[string]interface{} {
+	return c.changes
+}
+
+// Return the current changes as `url.Values`, like for building
+// a redirect query string. Numbers and booleans are formatted
+// with `strconv`, slices and arrays become repeated keys. Keys
+// are the fields param names, the ones read by `CastQuery`, and
+// fields tagged with "-" are left out.
+func (c Changeset[T]) ChangesToValues() url.Values {
+	values := make(url.Values, len(c.changes))
+
+	names := make(map[string]string)
+	for _, f := range exo.StructFields(c.data) {
+		names[f.Name] = exo.ParamName(f)
 	}
 
-	return true, nil
-}
+	for field, change := range c.changes {
+		if name, ok := names[field]; ok {
+			if name == "" {
+				continue
+			}
+			field = name
+		}
 
-// Validates if a `Number` field is greater than or equal to a given minimal value.
-type GreaterThanOrEqualValidator[T Number] struct {
-	MinValue T
-}
+		v := reflect.ValueOf(change)
 
-func (gtv GreaterThanOrEqualValidator[T]) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(T)
+		if k := v.Kind(); (k == reflect.Slice || k == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+			for i := 0; i < v.Len(); i++ {
+				values.Add(field, formatValue(v.Index(i)))
+			}
+			continue
+		}
 
-	if !ok {
-		return false, fmt.Errorf("isn't a Number %v", v)
+		values.Set(field, formatValue(v))
 	}
 
-	if v <= gtv.MinValue {
-		return false, fmt.Errorf("must be greater than or equal to %v", v)
+	return values
+}
+
+// Formats a single value for `ChangesToValues`.
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
 	}
 
-	return true, nil
+	switch {
+	case !v.IsValid():
+		return ""
+	case v.Kind() == reflect.String:
+		return v.String()
+	case v.Kind() == reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10)
+	case v.CanFloat():
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	}
+
+	return fmt.Sprint(v.Interface())
 }
 
-// Validates if a `Number` value is equal to a given exact value.
-type EqualToValidator[T Number] struct {
-	Value T
+// Return the underlying data the changeset was built over,
+// without any of the changes.
+func (c Changeset[T]) GetData() T {
+	return c.data
 }
 
-func (ev EqualToValidator[T]) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(T)
+// Return a copy of the underlying data with the changes of
+// fields without errors set, previewing the result of applying
+// the changeset. Unlike `ApplyNew`, invalid changesets don't
+// error and the changeset isn't marked as applied.
+func (c Changeset[T]) ChangesAsStruct() T {
+	s := c.data
+	ApplyValid(&s, c)
+	return s
+}
 
-	if !ok {
-		return false, fmt.Errorf("isn't a Number %v", v)
-	}
+// Alias of ChangesAsStruct, named after its use for re-rendering
+// a form after a failed validation: errored fields, including the
+// ones with indexed or nested errors like "Tags.1", keep their data
+// value, so users enter them again, while the other fields keep
+// the change.
+func (c Changeset[T]) EffectiveValid() T {
+	return c.ChangesAsStruct()
+}
 
-	if v == ev.Value {
-		return true, nil
+// Return the raw map that was gaved to `Cast`.
+func (c Changeset[T]) GetParams() map[string]interface{} {
+	return c.params
+}
+
+// Return a map of fields and their errors. Many errors
+// on the same field are joined, check `GetError`.
+func (c Changeset[T]) GetErrors() map[string]error {
+	out := make(map[string]error, len(c.errors))
+	for field := range c.errors {
+		out[field] = c.GetError(field)
 	}
 
-	return false, fmt.Errorf("must be equal to %v", v)
+	return out
 }
 
-// Validates if a `Number` value is different to a given exact value.
-type NotEqualToValidator[T Number] struct {
-	Value T
+// Return all errors for a field, in the order they were added.
+func (c Changeset[T]) GetFieldErrors(field string) []error {
+	return c.errors[field]
 }
 
-func (nev NotEqualToValidator[T]) Validate(field string, val interface{}) (bool, error) {
-	v, ok := val.(T)
-
-	if !ok {
-		return false, fmt.Errorf("isn't a Number %v", v)
-	}
+// Return a map of fields and their errors found while casting
+// the parameters, leaving out the errors added by validations.
+func (c Changeset[T]) CastErrors() map[string]error {
+	out := make(map[string]error)
 
-	if v != nev.Value {
-		return true, nil
+	for field, errs := range c.errors {
+		for _, err := range errs {
+			var ce *CastError
+			if errors.As(err, &ce) {
+				out[field] = err
+			}
+		}
 	}
 
-	return false, fmt.Errorf("must be not equal to %v", v)
+	return out
 }
 
-// Given a slice of fields names, validates if all of them
-// are present on the `changes` Changeset field, ensuring
-// their existence.
-func (c Changeset[T]) ValidateRequired(need []string) Changeset[T] {
-	for _, field := range need {
-		fieldValue, exists := c.changes[field]
+// Combine all errors into a single one with `errors.Join`,
+// each prefixed by its field name as a `FieldError`.
+// Return nil when the changeset is valid.
+func (c Changeset[T]) JoinErrors() error {
+	if c.IsValid {
+		return nil
+	}
 
-		if !exists || !reflect.ValueOf(fieldValue).IsValid() {
-			c.IsValid = false
-			c.errors[field] = errors.New("is required")
+	var errs []error
+	for _, field := range c.ErrorKeys() {
+		for _, err := range c.errors[field] {
+			errs = append(errs, FieldError{Field: field, Err: err})
 		}
 	}
 
-	return c
+	return errors.Join(errs...)
 }
 
-// Given a field and a instance of a `Validator`, apply the
-// validation on the changeset and if any error is present,
-// add it to the `errors` Changeset field, marking it as invalid.
-func (c Changeset[T]) ValidateChange(field string, v Validator) Changeset[T] {
-	val, ok := c.GetChange(field)
-	c.validations[field] = v
+// Return the errors as `FieldError`s ordered by the given
+// fields, like a form's display order. Errored fields missing
+// from the order come afterward, sorted by name.
+func (c Changeset[T]) OrderedErrorsBy(order []string) []FieldError {
+	var out []FieldError
+	seen := make(map[string]bool)
 
-	if !ok {
-		c.errors[field] = errors.New("doesn't exist")
-		c.IsValid = false
-		return c
-	}
+	for _, field := range append(append([]string{}, order...), c.ErrorKeys()...) {
+		if seen[field] {
+			continue
+		}
+		seen[field] = true
 
-	if ok, error := v.Validate(field, val); !ok {
-		c.errors[field] = error
-		c.IsValid = false
-		return c
+		for _, err := range c.errors[field] {
+			out = append(out, FieldError{Field: field, Err: err})
+		}
 	}
 
-	return c
+	return out
 }
 
-// Get the value of a `changes` entry.
-func (c Changeset[T]) GetChange(field string) (interface{}, bool) {
-	v, ok := c.changes[field]
+// Return the sorted names of the fields with errors.
+func (c Changeset[T]) ErrorKeys() []string {
+	keys := make([]string, 0, len(c.errors))
+	for field := range c.errors {
+		keys = append(keys, field)
+	}
+	sort.Strings(keys)
 
-	return v, ok
+	return keys
 }
 
-// Return all current changes that may be applied to the Changeset.
-func (c Changeset[T]) GetChanges() map[string]interface{} {
-	return c.changes
+// Whether the field has errors, either directly or on one
+// of its elements or nested fields, like "Tags.1".
+func (c Changeset[T]) hasErrors(field string) bool {
+	for key := range c.errors {
+		if key == field || strings.HasPrefix(key, field+".") {
+			return true
+		}
+	}
+
+	return false
 }
 
-// Return the raw map that was gaved to `Cast`.
-func (c Changeset[T]) GetParams() map[string]interface{} {
-	return c.params
+// Combine two changesets built over the same data, like one
+// from a controller and another from a service layer. On
+// conflicts, `b` takes precedence: its changes, params and
+// validations overwrite the ones from `a`. Errors of both are
+// kept and the result is only valid if both are. The underlying
+// data comes from `a`. Neither `a` nor `b` are modified.
+func Merge[T interface{}](a, b Changeset[T]) Changeset[T] {
+	c := a
+	c.applied = new(bool)
+	c.changes = make(map[string]interface{}, len(a.changes)+len(b.changes))
+	c.params = make(map[string]interface{}, len(a.params)+len(b.params))
+	c.errors = make(map[string][]error, len(a.errors)+len(b.errors))
+	c.validations = make(map[string]Validator, len(a.validations)+len(b.validations))
+	c.validators = make(map[string][]Validator, len(a.validators)+len(b.validators))
+	c.IsValid = a.IsValid && b.IsValid
+
+	for _, from := range []Changeset[T]{a, b} {
+		for field, change := range from.changes {
+			c.changes[field] = change
+		}
+		for key, param := range from.params {
+			c.params[key] = param
+		}
+		for field, v := range from.validations {
+			c.validations[field] = v
+		}
+		for field, vs := range from.validators {
+			c.validators[field] = append(c.validators[field], vs...)
+		}
+		for field, errs := range from.errors {
+			c.errors[field] = append(c.errors[field], errs...)
+		}
+	}
+
+	return c
 }
 
-// Return a map of fields and their errors.
-func (c Changeset[T]) GetErrors() map[string]error {
-	return c.errors
+// Given two snapshots of a changeset, like before and after
+// a re-validation, return the sorted fields whose errors
+// appeared and the ones whose errors disappeared.
+func ErrorDiff[T interface{}](before, after Changeset[T]) (added, removed []string) {
+	for _, field := range after.ErrorKeys() {
+		if _, ok := before.errors[field]; !ok {
+			added = append(added, field)
+		}
+	}
+
+	for _, field := range before.ErrorKeys() {
+		if _, ok := after.errors[field]; !ok {
+			removed = append(removed, field)
+		}
+	}
+
+	return added, removed
 }
 
-// Return a specific error for a field.
+// Return a specific error for a field. When the field
+// has many errors, they're combined with `errors.Join`.
 func (c Changeset[T]) GetError(field string) error {
-	return c.errors[field]
+	switch errs := c.errors[field]; len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	default:
+		return errors.Join(errs...)
+	}
 }
 
 // Applies a callback on each error and return a map
 // of fields and the transformed errors.
 // The callback will receive a reference to the changeset
 // the current error and the `Validator` that it failed.
+// Many errors on the same field are given joined, as
+// returned by `GetError`.
 func (c Changeset[T]) TraverseErrors(cb func(*Changeset[T], error, Validator) interface{}) map[string]interface{} {
 	var result = make(map[string]interface{}, len(c.errors))
 
-	for field, err := range c.errors {
-		final := cb(&c, err, c.validations[field])
+	for field := range c.errors {
+		final := cb(&c, c.GetError(field), c.validations[field])
 		result[field] = final
 	}
 
 	return result
 }
 
+// Same as TraverseErrors but the result map is typed after what
+// the callback returns, like `map[string]string` for messages.
+// It's a function since methods can't have type parameters.
+func TraverseErrorsTyped[T interface{}, R interface{}](c Changeset[T], cb func(*Changeset[T], error, Validator) R) map[string]R {
+	var result = make(map[string]R, len(c.errors))
+
+	for field := range c.errors {
+		result[field] = cb(&c, c.GetError(field), c.validations[field])
+	}
+
+	return result
+}
+
 // Return a map of fields and their applied `Validators`.
 func (c Changeset[T]) Validations() map[string]Validator {
 	return c.validations
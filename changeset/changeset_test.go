@@ -1,11 +1,15 @@
 package changeset_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/zoedsoupe/exo/changeset"
 )
@@ -28,6 +32,52 @@ func TestPutChange(t *testing.T) {
 	c = c.PutChange("B", "ixe")
 }
 
+func TestPutChanges(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{})
+
+	c = c.PutChanges(map[string]interface{}{"A": "oi", "B": 2})
+
+	if !c.IsValid {
+		t.Errorf("PutChanges should keep the changeset valid, got: %v", c.GetErrors())
+	}
+
+	if a, ok := c.GetChange("A"); !ok || a != "oi" {
+		t.Errorf("PutChanges should write every change, got: %v", a)
+	}
+
+	c = c.PutChanges(map[string]interface{}{"A": "hey", "B": "2"})
+
+	if c.IsValid || c.GetError("B") == nil {
+		t.Errorf("PutChanges should add errors on type mismatches, got: %v", c.GetErrors())
+	}
+
+	if a, _ := c.GetChange("A"); a != "hey" {
+		t.Errorf("PutChanges should write the valid changes, got: %v", a)
+	}
+}
+
+func TestDeleteChange(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{}).PutChange("A", "oi")
+
+	c = c.DeleteChange("A")
+
+	if _, ok := c.GetChange("A"); ok {
+		t.Errorf("DeleteChange should remove the change")
+	}
+
+	c = c.PutChange("B", "2")
+
+	if c.IsValid {
+		t.Errorf("PutChange should add error on type mismatch")
+	}
+
+	c = c.DeleteChange("B")
+
+	if !c.IsValid || c.GetError("B") != nil {
+		t.Errorf("DeleteChange should clear the field errors, got: %v", c.GetErrors())
+	}
+}
+
 func TestAddError(t *testing.T) {
 	attrs := map[string]interface{}{"A": "hello"}
 	c := changeset.Cast[T](attrs)
@@ -41,6 +91,78 @@ func TestAddError(t *testing.T) {
 	}
 }
 
+func TestFromConstraintError(t *testing.T) {
+	cases := map[string][2]string{
+		"users_name_key":       {"unique", "has already been taken"},
+		"users_pkey":           {"unique", "has already been taken"},
+		"users_name_unique":    {"unique", "has already been taken"},
+		"posts_user_id_fkey":   {"foreign_key", "does not exist"},
+		"users_age_check":      {"check", "is invalid"},
+		"bookings_period_excl": {"exclusion_constraint", "violates an exclusion constraint"},
+		"users_name_idx":       {"constraint", "is invalid"},
+	}
+
+	for constraint, expected := range cases {
+		c := changeset.Cast[U](map[string]interface{}{"Name": "bob"}).FromConstraintError("Name", constraint)
+
+		var ve *changeset.ValidationError
+		if err := c.GetError("Name"); c.IsValid || !errors.As(err, &ve) || ve.Key != expected[0] || ve.Error() != expected[1] {
+			t.Errorf("FromConstraintError should map %s to %v, got: %#v", constraint, expected, err)
+		}
+	}
+
+	c := changeset.Cast[U](map[string]interface{}{"Name": "bob"})
+	c = c.AddDBError("Name", errors.New("is locked"))
+
+	if err := c.GetError("Name"); c.IsValid || err == nil || err.Error() != "is locked" {
+		t.Errorf("AddDBError should add the error and mark the changeset as invalid, got: %v", err)
+	}
+}
+
+func TestClearError(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello"}
+	c := changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	if c.IsValid {
+		t.Errorf("ValidateChange should add error on wrong length")
+	}
+
+	c = c.ClearError("A")
+
+	if !c.IsValid || c.GetError("A") != nil {
+		t.Errorf("ClearError should remove the error and mark the changeset as valid")
+	}
+
+	if a, ok := c.GetChange("A"); !ok || a != "hello" {
+		t.Errorf("ClearError should keep the change, got: %v", a)
+	}
+}
+
+func TestResetValidations(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello", "B": "2"}
+	c := changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	c = c.ResetValidations()
+
+	if !c.IsValid || len(c.GetErrors()) != 0 || len(c.Validations()) != 0 {
+		t.Errorf("ResetValidations should clear errors and validations, got: %v %v", c.GetErrors(), c.Validations())
+	}
+
+	if a, ok := c.GetChange("A"); !ok || a != "hello" {
+		t.Errorf("ResetValidations should keep the changes, got: %v", a)
+	}
+
+	applied := changeset.Cast[T](map[string]interface{}{"A": "hello"})
+	var s T
+	if err := changeset.Apply(&s, applied); err != nil {
+		t.Errorf("Apply should apply valid changesets, got: %v", err)
+	}
+
+	if r := applied.ResetValidations(); r.IsApplied() {
+		t.Errorf("ResetValidations should return a new changeset not marked as applied")
+	}
+}
+
 func TestValidateRequired(t *testing.T) {
 	attrs := map[string]interface{}{}
 	c := changeset.Cast[T](attrs)
@@ -64,6 +186,93 @@ func TestValidateRequired(t *testing.T) {
 	}
 }
 
+type Address struct {
+	Country string
+	State   string
+}
+
+func TestValidateRequiredWhenEquals(t *testing.T) {
+	attrs := map[string]interface{}{"Country": "US"}
+	c := changeset.Cast[Address](attrs).ValidateRequiredWhenEquals("State", "Country", "US")
+
+	if c.IsValid || c.GetError("State") == nil {
+		t.Errorf("ValidateRequiredWhenEquals should require the field when the other field matches")
+	}
+
+	attrs = map[string]interface{}{"Country": "CA"}
+	c = changeset.Cast[Address](attrs).ValidateRequiredWhenEquals("State", "Country", "US")
+
+	if !c.IsValid || c.GetError("State") != nil {
+		t.Errorf("ValidateRequiredWhenEquals shouldn't require the field when the other field differs")
+	}
+}
+
+func TestValidateSumEquals(t *testing.T) {
+	attrs := map[string]interface{}{"X": 50, "Y": 30, "Z": 20}
+	c := changeset.Cast[Split](attrs).ValidateSumEquals([]string{"X", "Y", "Z"}, 100)
+
+	if !c.IsValid {
+		t.Errorf("ValidateSumEquals shouldn't add error when the sum matches, got: %v", c.GetErrors())
+	}
+
+	attrs = map[string]interface{}{"X": 50, "Y": 30, "Z": 10}
+	c = changeset.Cast[Split](attrs).ValidateSumEquals([]string{"X", "Y", "Z"}, 100)
+
+	if err := c.GetError(changeset.BaseKey); c.IsValid || err == nil || err.Error() != "X, Y, Z must sum up to 100" {
+		t.Errorf("ValidateSumEquals should add error on the base key when the sum differs, got: %v", err)
+	}
+
+	c2 := changeset.Cast[T](map[string]interface{}{"A": "1", "B": 2}).ValidateSumEquals([]string{"A", "B"}, 3)
+
+	if c2.IsValid || c2.GetError("A") == nil {
+		t.Errorf("ValidateSumEquals should add error on non numeric fields")
+	}
+}
+
+type Doc struct{ Version int }
+
+func TestValidateIncreasing(t *testing.T) {
+	curr := Doc{Version: 3}
+
+	c := changeset.CastFrom(curr, map[string]interface{}{"Version": 4}).ValidateIncreasing("Version")
+	if !c.IsValid {
+		t.Errorf("ValidateIncreasing shouldn't add error on increased values, got: %v", c.GetErrors())
+	}
+
+	c = changeset.CastFrom(curr, map[string]interface{}{"Version": 2}).ValidateIncreasing("Version")
+	if err := c.GetError("Version"); c.IsValid || err == nil || err.Error() != "must increase" {
+		t.Errorf("ValidateIncreasing should add error on decreased values, got: %v", err)
+	}
+}
+
+type Signup struct {
+	Password             string
+	PasswordConfirmation string
+}
+
+func TestValidateConfirmation(t *testing.T) {
+	attrs := map[string]interface{}{"Password": "secret", "PasswordConfirmation": "secret"}
+	c := changeset.Cast[Signup](attrs).ValidateConfirmation("Password", "PasswordConfirmation")
+
+	if !c.IsValid {
+		t.Errorf("ValidateConfirmation shouldn't add error on matching fields, got: %v", c.GetErrors())
+	}
+
+	attrs = map[string]interface{}{"Password": "secret", "PasswordConfirmation": "secrets"}
+	c = changeset.Cast[Signup](attrs).ValidateConfirmation("Password", "PasswordConfirmation")
+
+	if err := c.GetError("PasswordConfirmation"); c.IsValid || err == nil || err.Error() != "does not match" {
+		t.Errorf("ValidateConfirmation should add error on mismatching fields, got: %v", err)
+	}
+
+	attrs = map[string]interface{}{"Password": "secret"}
+	c = changeset.Cast[Signup](attrs).ValidateConfirmation("Password", "PasswordConfirmation")
+
+	if c.IsValid || c.GetError("PasswordConfirmation") == nil {
+		t.Errorf("ValidateConfirmation should add error on a missing confirmation")
+	}
+}
+
 func TestUpdateChange(t *testing.T) {
 	attrs := map[string]interface{}{"A": "hello"}
 	c := changeset.Cast[T](attrs)
@@ -95,6 +304,18 @@ func TestValidateAcceptance(t *testing.T) {
 	}
 }
 
+func TestValidateExclusion(t *testing.T) {
+	ev := changeset.ExclusionValidator{Disallowed: []interface{}{"root", "admin"}}
+
+	if ok, err := ev.Validate("A", "admin"); ok || err.Error() != "is reserved" {
+		t.Errorf("ExclusionValidator should fail on disallowed values, got: %v", err)
+	}
+
+	if ok, err := ev.Validate("A", "bob"); !ok {
+		t.Errorf("ExclusionValidator should pass on allowed values, got: %v", err)
+	}
+}
+
 func TestValidateFormat(t *testing.T) {
 	attrs := map[string]interface{}{"A": "hello"}
 	c := changeset.Cast[T](attrs)
@@ -113,6 +334,33 @@ func TestValidateFormat(t *testing.T) {
 	}
 }
 
+func TestMultipleErrors(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello"}
+	c := changeset.Cast[T](attrs).
+		ValidateChange("A", changeset.FormatValidator{Pattern: regexp.MustCompile("^[0-9]+$")}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	errs := c.GetFieldErrors("A")
+	if len(errs) != 2 || errs[0].Error() != "has invalid format" || errs[1].Error() != "should be at most 2 characters" {
+		t.Errorf("ValidateChange should keep every error on a field, got: %v", errs)
+	}
+
+	if err := c.GetError("A"); !errors.Is(err, errs[0]) || !errors.Is(err, errs[1]) {
+		t.Errorf("GetError should join every error on a field, got: %v", err)
+	}
+
+	if msg := c.ErrorJSON()["A"]; msg != "has invalid format, should be at most 2 characters" {
+		t.Errorf("ErrorJSON should join every message on a field, got: %q", msg)
+	}
+
+	traversed := c.TraverseErrors(func(_ *changeset.Changeset[T], err error, _ changeset.Validator) interface{} {
+		return err.Error()
+	})
+	if msg := traversed["A"]; msg != "has invalid format\nshould be at most 2 characters" {
+		t.Errorf("TraverseErrors should give every error on a field, got: %q", msg)
+	}
+}
+
 func TestApply(t *testing.T) {
 	var curr T
 	curr.A = "old value"
@@ -156,106 +404,2193 @@ func TestApplyNew(t *testing.T) {
 	}
 }
 
-type R struct{ A int }
+type Tagged struct{ Tags []string }
 
-func TestValidateLength(t *testing.T) {
-	attrs := map[string]interface{}{"A": "hello"}
-	c := changeset.Cast[T](attrs)
+func TestDeepApply(t *testing.T) {
+	tags := []string{"a", "b"}
+	c := changeset.Cast[Tagged](map[string]interface{}{"Tags": tags})
 
-	lv1 := changeset.LengthValidator{Min: 5, Max: 5}
-	if c := c.ValidateChange("A", lv1); !c.IsValid {
-		t.Errorf("ValidateChange should return truthy on correct length")
+	var curr Tagged
+	if err := changeset.DeepApply(&curr, c); err != nil {
+		t.Errorf("DeepApply should apply valid changesets, got: %v", err)
 	}
 
-	lv2 := changeset.LengthValidator{Min: 10, Max: 10}
-	if c := c.ValidateChange("A", lv2); c.IsValid {
-		t.Errorf("ValidateChange should return falsy on wrong length")
+	tags[0] = "z"
+
+	if curr.Tags[0] != "a" {
+		t.Errorf("DeepApply shouldn't share backing storage with the changes, got: %v", curr.Tags)
 	}
+}
 
-	attrs = map[string]interface{}{"A": 20}
-	c2 := changeset.Cast[R](attrs)
+type Profile struct {
+	BillingName string
+	BillingCity string
+	Nickname    string
+}
 
-	lvc2 := changeset.LengthValidator{Min: 5, Max: 5}
-	if c2 := c2.ValidateChange("A", lvc2); c2.IsValid {
-		t.Errorf("ValidateChange should return error on non string and slices types")
+func TestApplyWhere(t *testing.T) {
+	attrs := map[string]interface{}{"BillingName": "Bob", "BillingCity": "Rio", "Nickname": "bobby"}
+	c := changeset.Cast[Profile](attrs)
+
+	curr := Profile{Nickname: "old"}
+	err := changeset.ApplyWhere(&curr, c, func(field string) bool {
+		return strings.HasPrefix(field, "Billing")
+	})
+
+	if err != nil {
+		t.Errorf("ApplyWhere should apply valid changesets, got: %v", err)
+	}
+
+	if curr.BillingName != "Bob" || curr.BillingCity != "Rio" {
+		t.Errorf("ApplyWhere should apply the fields matching the predicate, got: %v", curr)
+	}
+
+	if curr.Nickname != "old" {
+		t.Errorf("ApplyWhere shouldn't apply the fields not matching the predicate, got: %v", curr)
+	}
+
+	if _, ok := c.GetChange("Nickname"); !ok {
+		t.Errorf("ApplyWhere should keep the unapplied changes staged")
 	}
 }
 
-func TestCast(t *testing.T) {
-	attrs := map[string]interface{}{"foo": 123, "A": "hello", "B": 2}
-	c := changeset.Cast[T](attrs)
+func TestApplyValid(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello", "B": 42}
+	c := changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
 
-	if p := c.GetParams(); !reflect.DeepEqual(p, attrs) {
-		t.Errorf("Cast should parse params as raw attrs, got: %v", p)
+	curr := T{A: "old"}
+	skipped := changeset.ApplyValid(&curr, c)
+
+	if !reflect.DeepEqual(skipped, []string{"A"}) {
+		t.Errorf("ApplyValid should return the errored fields, got: %v", skipped)
 	}
 
-	if _, f := c.GetChange("foo"); f {
-		t.Errorf("Cast should't return the 'foo' key")
+	if curr.A != "old" || curr.B != 42 {
+		t.Errorf("ApplyValid should only apply the fields without errors, got: %v", curr)
+	}
+}
+
+func TestApplyValidNested(t *testing.T) {
+	fv := changeset.FormatValidator{Pattern: regexp.MustCompile(`^[a-z]+$`)}
+	c := changeset.CastFrom(Tagged{Tags: []string{"old"}}, map[string]interface{}{"Tags": []string{"go", "Elixir"}}).
+		ValidateEach("Tags", fv)
+
+	curr := Tagged{Tags: []string{"old"}}
+	skipped := changeset.ApplyValid(&curr, c)
+
+	if !reflect.DeepEqual(skipped, []string{"Tags.1"}) {
+		t.Errorf("ApplyValid should return the indexed error keys, got: %v", skipped)
 	}
 
-	if _, f := c.GetChange("A"); !f {
-		t.Errorf("Cast should return the 'A' key")
+	if !reflect.DeepEqual(curr.Tags, []string{"old"}) {
+		t.Errorf("ApplyValid shouldn't apply fields with indexed errors, got: %v", curr.Tags)
 	}
 
-	if _, f := c.GetChange("B"); !f {
-		t.Errorf("Cast should return the 'B' key")
+	if s := c.ChangesAsStruct(); !reflect.DeepEqual(s.Tags, []string{"old"}) {
+		t.Errorf("ChangesAsStruct should keep the data on fields with indexed errors, got: %v", s.Tags)
 	}
 }
 
-func TestGetChange(t *testing.T) {
-	attrs := map[string]interface{}{"foo": 123, "A": "hello", "B": 2}
+func TestApplyRef(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello"}
+	s, c := changeset.ApplyRef(changeset.Cast[T](attrs))
+
+	if s == nil || s.A != "hello" {
+		t.Errorf("ApplyRef should return the applied struct, got: %v", s)
+	}
+
+	if c == nil || !c.IsValid {
+		t.Errorf("ApplyRef should return the valid changeset")
+	}
+
+	attrs = map[string]interface{}{"A": 123}
+	s, c = changeset.ApplyRef(changeset.Cast[T](attrs))
+
+	if s != nil {
+		t.Errorf("ApplyRef should return a nil struct on invalid changesets, got: %v", s)
+	}
+
+	if c == nil || c.IsValid || c.GetError("A") == nil {
+		t.Errorf("ApplyRef should return the invalid changeset with its errors")
+	}
+}
+
+func TestApplyJSON(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello", "B": 2}
+	b, err := changeset.ApplyJSON(changeset.Cast[T](attrs))
+
+	if err != nil || string(b) != `{"A":"hello","B":2}` {
+		t.Errorf("ApplyJSON should return the applied struct as JSON, got: %s %v", b, err)
+	}
+
+	attrs = map[string]interface{}{"A": 123}
+	b, err = changeset.ApplyJSON(changeset.Cast[T](attrs))
+
+	var c *changeset.Changeset[T]
+	if b != nil || !errors.As(err, &c) {
+		t.Errorf("ApplyJSON should return the changeset as error when invalid, got: %v", err)
+	}
+}
+
+func TestApplyTwice(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello"}
 	c := changeset.Cast[T](attrs)
 
-	if v, ok := c.GetChange("A"); !ok || !reflect.DeepEqual(v, "hello") {
-		t.Errorf("GetChange should return the correct value from key, got: %v", v)
+	if _, err := changeset.ApplyNew(c); err != nil || !c.IsApplied() {
+		t.Errorf("ApplyNew should apply and mark the changeset as applied, got: %v", err)
 	}
 
-	if v, ok := c.GetChange("B"); !ok || !reflect.DeepEqual(v, 2) {
-		t.Errorf("GetChange should return the correct value from key, got: %v", v)
+	if _, err := changeset.ApplyNew(c); !errors.Is(err, changeset.ErrAlreadyApplied) {
+		t.Errorf("ApplyNew should return an error on already applied changesets, got: %v", err)
+	}
+
+	c = c.Reset()
+
+	if _, err := changeset.ApplyNew(c); err != nil {
+		t.Errorf("ApplyNew should apply reset changesets, got: %v", err)
 	}
 }
 
-func TestTraverseErrors(t *testing.T) {
-	attrs := map[string]interface{}{"A": 123, "B": "hello"}
-	c := changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2}).ValidateChange("B", changeset.EqualToValidator[int]{Value: 2})
+func TestApplyDerived(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "hello"})
 
-	errors := c.TraverseErrors(parseErrors)
+	var s T
+	if err := changeset.Apply(&s, c); err != nil {
+		t.Errorf("Apply should apply valid changesets, got: %v", err)
+	}
 
-	err := errors["A"]
-	if err != "it's sucha minimal: 1" {
-		t.Errorf("TraverErrors should return the parsed error accordingly to the function gaved, returned: %v", err)
+	derived := c.PutChange("A", "world")
+
+	if derived.IsApplied() || !c.IsApplied() {
+		t.Errorf("PutChange should return a new changeset not marked as applied")
 	}
 
-	err = errors["B"]
-	if err != "wrong value: 2" {
-		t.Errorf("TraverErrors should return the parsed error accordingly to the function gaved, returned: %v", err)
+	if err := changeset.Apply(&s, derived); err != nil || s.A != "world" {
+		t.Errorf("Apply should apply changesets derived from applied ones, got: %v %v", s, err)
+	}
+
+	if !derived.IsApplied() || !c.IsApplied() {
+		t.Errorf("Apply should only mark the changeset it applied")
 	}
 }
 
-func parseErrors[T interface{}](_c *changeset.Changeset[T], err error, v changeset.Validator) interface{} {
-	switch v.(type) {
-	case changeset.LengthValidator:
-		validator := v.(changeset.LengthValidator)
-		return fmt.Sprintf("it's sucha minimal: %d", validator.Min)
+func TestApplyTwiceEveryEntryPoint(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello", "B": 2}
+	c := changeset.Cast[T](attrs)
 
-	case changeset.EqualToValidator[int]:
-		validator := v.(changeset.EqualToValidator[int])
-		return fmt.Sprintf("wrong value: %d", validator.Value)
+	if _, err := changeset.ApplyMap(c); err != nil || !c.IsApplied() {
+		t.Errorf("ApplyMap should apply and mark the changeset as applied, got: %v", err)
+	}
 
-	default:
-		return "I don't know"
+	if m, err := changeset.ApplyMap(c); m != nil || !errors.Is(err, changeset.ErrAlreadyApplied) {
+		t.Errorf("ApplyMap should return an error on already applied changesets, got: %v", err)
+	}
+
+	var v T
+	if skipped := changeset.ApplyValid(&v, c); len(skipped) != 0 || v.A != "hello" {
+		t.Errorf("ApplyValid should apply already applied changesets, got: %v", v)
+	}
+
+	c = changeset.Cast[T](attrs)
+	changeset.ApplyValid(&v, c)
+
+	if c.IsApplied() {
+		t.Errorf("ApplyValid shouldn't mark the changeset as applied")
+	}
+
+	var s T
+	all := func(string) bool { return true }
+	if err := changeset.ApplyWhere(&s, c, all); err != nil || !c.IsApplied() {
+		t.Errorf("ApplyWhere should apply and mark the changeset as applied, got: %v", err)
+	}
+
+	if err := changeset.ApplyWhere(&s, c, all); !errors.Is(err, changeset.ErrAlreadyApplied) {
+		t.Errorf("ApplyWhere should return an error on already applied changesets, got: %v", err)
+	}
+
+	c = changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 10})
+	if err := changeset.ApplyRevalidated(&s, c); err != nil || !c.IsApplied() {
+		t.Errorf("ApplyRevalidated should apply and mark the changeset as applied, got: %v", err)
+	}
+
+	if err := changeset.ApplyRevalidated(&s, c); !errors.Is(err, changeset.ErrAlreadyApplied) {
+		t.Errorf("ApplyRevalidated should return an error on already applied changesets, got: %v", err)
+	}
+
+	c = changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 10}).PutChange("A", "hello world")
+	if err := changeset.ApplyRevalidated(&s, c); err == nil || !c.IsApplied() {
+		t.Errorf("ApplyRevalidated should mark partially applied changesets as applied, got: %v", err)
+	}
+
+	if err := changeset.ApplyRevalidated(&s, c); !errors.Is(err, changeset.ErrAlreadyApplied) {
+		t.Errorf("ApplyRevalidated should check the guard before a partial apply, got: %v", err)
 	}
 }
 
-func TestIsFieldMissing(t *testing.T) {
+type R struct{ A int }
+
+func TestValidateLength(t *testing.T) {
 	attrs := map[string]interface{}{"A": "hello"}
 	c := changeset.Cast[T](attrs)
 
-	if !c.IsFieldMissing("B") {
-		t.Errorf("IsFieldMissing should only return false on a existing field on the changeset")
+	lv1 := changeset.LengthValidator{Min: 5, Max: 5}
+	if c := c.ValidateChange("A", lv1); !c.IsValid {
+		t.Errorf("ValidateChange should return truthy on correct length")
 	}
 
-	if c.IsFieldMissing("A") {
-		t.Errorf("IsFieldMissing should only return true on a missing field on the changeset")
+	lv2 := changeset.LengthValidator{Min: 10, Max: 10}
+	if c := c.ValidateChange("A", lv2); c.IsValid {
+		t.Errorf("ValidateChange should return falsy on wrong length")
+	}
+
+	attrs = map[string]interface{}{"A": 20}
+	c2 := changeset.Cast[R](attrs)
+
+	lvc2 := changeset.LengthValidator{Min: 5, Max: 5}
+	if c2 := c2.ValidateChange("A", lvc2); c2.IsValid {
+		t.Errorf("ValidateChange should return error on non string and slices types")
+	}
+}
+
+type Collections struct {
+	Tags   []string
+	Scores map[string]int
+}
+
+type Account struct {
+	AccountType string
+	CompanyName string
+}
+
+func TestValidateChangeIf(t *testing.T) {
+	isBusiness := func(c *changeset.Changeset[Account]) bool {
+		kind, _ := c.GetChange("AccountType")
+		return kind == "business"
+	}
+
+	attrs := map[string]interface{}{"AccountType": "business", "CompanyName": " "}
+	c := changeset.Cast[Account](attrs).ValidateChangeIf(isBusiness, "CompanyName", changeset.PresenceValidator{})
+
+	if c.IsValid || c.GetError("CompanyName") == nil {
+		t.Errorf("ValidateChangeIf should validate when the condition holds")
+	}
+
+	attrs = map[string]interface{}{"AccountType": "personal", "CompanyName": " "}
+	c = changeset.Cast[Account](attrs).ValidateChangeIf(isBusiness, "CompanyName", changeset.PresenceValidator{})
+
+	if !c.IsValid || c.GetError("CompanyName") != nil || len(c.Validations()) != 0 {
+		t.Errorf("ValidateChangeIf shouldn't validate when the condition doesn't hold")
+	}
+}
+
+func TestValidateChanges(t *testing.T) {
+	attrs := map[string]interface{}{"Country": "BR", "State": "Rio de Janeiro"}
+	c := changeset.Cast[Address](attrs).ValidateChanges([]string{"Country", "State"}, changeset.LengthValidator{Min: 2, Max: 2})
+
+	if c.IsValid {
+		t.Errorf("ValidateChanges should mark the changeset as invalid when a field fails")
+	}
+
+	if err := c.GetError("Country"); err != nil {
+		t.Errorf("ValidateChanges shouldn't add error on passing fields, got: %v", err)
+	}
+
+	if err := c.GetError("State"); err == nil {
+		t.Errorf("ValidateChanges should add error on failing fields")
+	}
+
+	c = changeset.Cast[Address](map[string]interface{}{}).ValidateChanges([]string{"Country"}, changeset.LengthValidator{Min: 2, Max: 2})
+
+	if err := c.GetError("Country"); err == nil || err.Error() != "doesn't exist" {
+		t.Errorf("ValidateChanges should add error on missing fields, got: %v", err)
+	}
+}
+
+func TestValidateLengthCollections(t *testing.T) {
+	attrs := map[string]interface{}{"Tags": []string{"a", "b"}, "Scores": map[string]int{"a": 1, "b": 2}}
+	lv := changeset.LengthValidator{Min: 1, Max: 2}
+
+	c := changeset.Cast[Collections](attrs).ValidateChange("Tags", lv).ValidateChange("Scores", lv)
+	if !c.IsValid {
+		t.Errorf("ValidateChange should pass on slices and maps within bounds, got: %v", c.GetErrors())
+	}
+
+	lv = changeset.LengthValidator{Min: 3, Max: 5}
+
+	c = changeset.Cast[Collections](attrs).ValidateChange("Tags", lv)
+	if err := c.GetError("Tags"); err == nil || err.Error() != "should have at least 3 items" {
+		t.Errorf("ValidateChange should fail on slices outside bounds, got: %v", err)
+	}
+
+	c = changeset.Cast[Collections](attrs).ValidateChange("Scores", lv)
+	if err := c.GetError("Scores"); err == nil || err.Error() != "should have at least 3 elements" {
+		t.Errorf("ValidateChange should fail on maps outside bounds, got: %v", err)
+	}
+}
+
+func TestValidateLengthGraphemes(t *testing.T) {
+	// a thumbs up with a skin tone and a family joined by ZWJ:
+	// 2 graphemes made of 7 runes
+	attrs := map[string]interface{}{"A": "\U0001F44D\U0001F3FD\U0001F468\u200D\U0001F469\u200D\U0001F467"}
+	c := changeset.Cast[T](attrs)
+
+	lv := changeset.LengthValidator{Min: 1, Max: 3, CountGraphemes: true}
+	if c := c.ValidateChange("A", lv); !c.IsValid {
+		t.Errorf("ValidateChange should count graphemes when CountGraphemes is set, got: %v", c.GetError("A"))
+	}
+
+	lv = changeset.LengthValidator{Min: 1, Max: 3, CountRunes: true}
+	if c := c.ValidateChange("A", lv); c.IsValid {
+		t.Errorf("ValidateChange should count runes when CountRunes is set")
+	}
+
+	lv = changeset.LengthValidator{Min: 7, Max: 7, CountRunes: true}
+	if c := c.ValidateChange("A", lv); !c.IsValid {
+		t.Errorf("ValidateChange should count every rune when CountRunes is set, got: %v", c.GetError("A"))
+	}
+}
+
+func TestCast(t *testing.T) {
+	attrs := map[string]interface{}{"foo": 123, "A": "hello", "B": 2}
+	c := changeset.Cast[T](attrs)
+
+	if p := c.GetParams(); !reflect.DeepEqual(p, attrs) {
+		t.Errorf("Cast should parse params as raw attrs, got: %v", p)
+	}
+
+	if _, f := c.GetChange("foo"); f {
+		t.Errorf("Cast should't return the 'foo' key")
+	}
+
+	if _, f := c.GetChange("A"); !f {
+		t.Errorf("Cast should return the 'A' key")
+	}
+
+	if _, f := c.GetChange("B"); !f {
+		t.Errorf("Cast should return the 'B' key")
+	}
+}
+
+type U struct{ Name string }
+
+type Person struct {
+	FirstName string `exo:"first_name"`
+	LastName  string `json:"last_name,omitempty"`
+	Age       int
+	Secret    string `json:"-"`
+}
+
+func TestCastTags(t *testing.T) {
+	attrs := map[string]interface{}{"first_name": "Ada", "last_name": "Lovelace", "Age": 36, "Secret": "x", "FirstName": "Bob"}
+	c := changeset.Cast[Person](attrs)
+
+	expected := map[string]interface{}{"FirstName": "Ada", "LastName": "Lovelace", "Age": 36}
+	if changes := c.GetChanges(); !reflect.DeepEqual(changes, expected) {
+		t.Errorf("Cast should match params by tags and key changes by field name, got: %v", changes)
+	}
+
+	p, err := changeset.ApplyNew(c)
+	if err != nil || p.FirstName != "Ada" || p.LastName != "Lovelace" {
+		t.Errorf("Apply should apply the tagged fields, got: %v", p)
+	}
+}
+
+func TestCastWithEmptyStringAsAbsent(t *testing.T) {
+	attrs := map[string]interface{}{"A": "", "B": 0}
+
+	c := changeset.CastWith[T](attrs, changeset.CastOptions{EmptyStringAsAbsent: true})
+
+	if _, ok := c.GetChange("A"); ok {
+		t.Errorf("CastWith shouldn't stage empty strings when EmptyStringAsAbsent is set")
+	}
+
+	if _, ok := c.GetChange("B"); !ok {
+		t.Errorf("CastWith should stage non string zero values")
+	}
+
+	if c := c.ValidateRequired([]string{"A"}); c.IsValid {
+		t.Errorf("ValidateRequired should add error on empty strings treated as absent")
+	}
+
+	if _, ok := changeset.CastWith[T](attrs, changeset.CastOptions{}).GetChange("A"); !ok {
+		t.Errorf("CastWith should stage empty strings by default")
+	}
+}
+
+type Entity struct{ ID int }
+
+type Customer struct {
+	Entity
+	Name string
+}
+
+func TestCastEmbedded(t *testing.T) {
+	c := changeset.Cast[Customer](map[string]interface{}{"ID": 7, "Name": "bob"})
+
+	got, err := changeset.ApplyNew(c)
+	if err != nil || got.ID != 7 || got.Name != "bob" {
+		t.Errorf("Cast should populate embedded fields, got: %v %v", got, err)
+	}
+}
+
+func TestCastRenamed(t *testing.T) {
+	attrs := map[string]interface{}{"user_name": "bob"}
+	c := changeset.CastRenamed[U](attrs, map[string]string{"user_name": "Name"})
+
+	if v, ok := c.GetChange("Name"); !ok || v != "bob" {
+		t.Errorf("CastRenamed should cast the renamed key into the field, got: %v", v)
+	}
+
+	if _, ok := c.GetChange("user_name"); ok {
+		t.Errorf("CastRenamed shouldn't keep the original key as a change")
+	}
+
+	if p := c.GetParams(); !reflect.DeepEqual(p, attrs) {
+		t.Errorf("CastRenamed should keep the raw params, got: %v", p)
+	}
+}
+
+func TestCastRenamedTagged(t *testing.T) {
+	attrs := map[string]interface{}{"fname": "jane", "lname": "doe", "pw": "s3cr3t"}
+	mapping := map[string]string{"fname": "FirstName", "lname": "LastName", "pw": "Secret"}
+	c := changeset.CastRenamed[Person](attrs, mapping)
+
+	if v, ok := c.GetChange("FirstName"); !ok || v != "jane" {
+		t.Errorf("CastRenamed should rename into an exo tagged field, got: %v", v)
+	}
+
+	if v, ok := c.GetChange("LastName"); !ok || v != "doe" {
+		t.Errorf("CastRenamed should rename into a json tagged field, got: %v", v)
+	}
+
+	if v, ok := c.GetChange("Secret"); ok {
+		t.Errorf("CastRenamed shouldn't cast a field tagged \"-\", got: %v", v)
+	}
+}
+
+func TestCastErrors(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello", "B": "2"}
+	c := changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	errs := c.CastErrors()
+
+	if _, ok := errs["B"]; !ok {
+		t.Errorf("CastErrors should return the type mismatch errors, got: %v", errs)
+	}
+
+	if _, ok := errs["A"]; ok || len(errs) != 1 {
+		t.Errorf("CastErrors shouldn't return the validation errors, got: %v", errs)
+	}
+}
+
+func TestSafeCast(t *testing.T) {
+	if _, err := changeset.SafeCast[int](map[string]interface{}{"A": 1}); err == nil || err.Error() != "cast failed: argument is not a struct" {
+		t.Errorf("SafeCast should return an error on non struct types, got: %v", err)
+	}
+
+	c, err := changeset.SafeCast[T](map[string]interface{}{"A": "hello"})
+
+	if err != nil || !c.IsValid {
+		t.Errorf("SafeCast should cast struct types, got: %v", err)
+	}
+}
+
+func TestGetChange(t *testing.T) {
+	attrs := map[string]interface{}{"foo": 123, "A": "hello", "B": 2}
+	c := changeset.Cast[T](attrs)
+
+	if v, ok := c.GetChange("A"); !ok || !reflect.DeepEqual(v, "hello") {
+		t.Errorf("GetChange should return the correct value from key, got: %v", v)
+	}
+
+	if v, ok := c.GetChange("B"); !ok || !reflect.DeepEqual(v, 2) {
+		t.Errorf("GetChange should return the correct value from key, got: %v", v)
+	}
+}
+
+func TestTraverseErrors(t *testing.T) {
+	attrs := map[string]interface{}{"A": 123, "B": "hello"}
+	c := changeset.Cast[T](attrs).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2}).ValidateChange("B", changeset.EqualToValidator[int]{Value: 2})
+
+	errors := c.TraverseErrors(parseErrors)
+
+	err := errors["A"]
+	if err != "it's sucha minimal: 1" {
+		t.Errorf("TraverErrors should return the parsed error accordingly to the function gaved, returned: %v", err)
+	}
+
+	err = errors["B"]
+	if err != "wrong value: 2" {
+		t.Errorf("TraverErrors should return the parsed error accordingly to the function gaved, returned: %v", err)
+	}
+}
+
+func parseErrors[T interface{}](_c *changeset.Changeset[T], err error, v changeset.Validator) interface{} {
+	switch v.(type) {
+	case changeset.LengthValidator:
+		validator := v.(changeset.LengthValidator)
+		return fmt.Sprintf("it's sucha minimal: %d", validator.Min)
+
+	case changeset.EqualToValidator[int]:
+		validator := v.(changeset.EqualToValidator[int])
+		return fmt.Sprintf("wrong value: %d", validator.Value)
+
+	default:
+		return "I don't know"
+	}
+}
+
+func TestErrorDiff(t *testing.T) {
+	before := changeset.Cast[Split](map[string]interface{}{"X": "1", "Y": "2"})
+	after := changeset.Cast[Split](map[string]interface{}{"Y": "2", "Z": "3"})
+
+	if keys := before.ErrorKeys(); !reflect.DeepEqual(keys, []string{"X", "Y"}) {
+		t.Errorf("ErrorKeys should return the sorted errored fields, got: %v", keys)
+	}
+
+	added, removed := changeset.ErrorDiff(before, after)
+
+	if !reflect.DeepEqual(added, []string{"Z"}) {
+		t.Errorf("ErrorDiff should return the newly errored fields, got: %v", added)
+	}
+
+	if !reflect.DeepEqual(removed, []string{"X"}) {
+		t.Errorf("ErrorDiff should return the fields no longer errored, got: %v", removed)
+	}
+}
+
+func TestOrderedErrorsBy(t *testing.T) {
+	c := changeset.Cast[Split](map[string]interface{}{}).
+		AddError("X", errors.New("is invalid")).
+		AddError("Y", errors.New("is invalid")).
+		AddError("Z", errors.New("is invalid"))
+
+	var fields []string
+	for _, fe := range c.OrderedErrorsBy([]string{"Z", "X"}) {
+		fields = append(fields, fe.Field)
+	}
+
+	if !reflect.DeepEqual(fields, []string{"Z", "X", "Y"}) {
+		t.Errorf("OrderedErrorsBy should follow the given order, got: %v", fields)
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	if err := changeset.Cast[T](map[string]interface{}{"A": "hello"}).JoinErrors(); err != nil {
+		t.Errorf("JoinErrors should return nil on valid changesets, got: %v", err)
+	}
+
+	errRequired := errors.New("is required")
+	c := changeset.Cast[T](map[string]interface{}{"B": "2"})
+	c = c.AddError("A", errRequired)
+
+	err := c.JoinErrors()
+
+	if msg := err.Error(); msg != "A: is required\nB: type mismatch: expect int got string" {
+		t.Errorf("JoinErrors should prefix each error with its field, got: %q", msg)
+	}
+
+	if !errors.Is(err, errRequired) {
+		t.Errorf("JoinErrors should allow unwrapping the field errors")
+	}
+
+	var fe changeset.FieldError
+	if !errors.As(err, &fe) || fe.Field != "A" {
+		t.Errorf("JoinErrors should be made of FieldError, got: %v", fe)
+	}
+}
+
+func TestIsFieldMissing(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello"}
+	c := changeset.Cast[T](attrs)
+
+	if !c.IsFieldMissing("B") {
+		t.Errorf("IsFieldMissing should only return false on a existing field on the changeset")
+	}
+
+	if c.IsFieldMissing("A") {
+		t.Errorf("IsFieldMissing should only return true on a missing field on the changeset")
+	}
+}
+
+type Schedule struct {
+	Every time.Duration
+	At    time.Time
+}
+
+func TestCastCoerce(t *testing.T) {
+	attrs := map[string]interface{}{"Every": 3600, "At": 1700000000}
+	c := changeset.CastCoerce[Schedule](attrs)
+
+	if !c.IsValid {
+		t.Errorf("CastCoerce should convert numeric params, got: %v", c.GetErrors())
+	}
+
+	if v, _ := c.GetChange("Every"); v != time.Hour {
+		t.Errorf("CastCoerce should read durations as seconds, got: %v", v)
+	}
+
+	if v, _ := c.GetChange("At"); !v.(time.Time).Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("CastCoerce should read times as Unix timestamps, got: %v", v)
+	}
+
+	if c := changeset.Cast[Schedule](attrs); c.IsValid {
+		t.Errorf("Cast shouldn't coerce params")
+	}
+}
+
+type Query struct {
+	Page   int
+	Active bool
+	Ratio  float32
+}
+
+func TestCastCoerceStrings(t *testing.T) {
+	attrs := map[string]interface{}{"Page": "42", "Active": "true", "Ratio": "0.5"}
+	c := changeset.CastCoerce[Query](attrs)
+
+	expected := map[string]interface{}{"Page": 42, "Active": true, "Ratio": float32(0.5)}
+	if changes := c.GetChanges(); !c.IsValid || !reflect.DeepEqual(changes, expected) {
+		t.Errorf("CastCoerce should parse strings into the field types, got: %v", changes)
+	}
+
+	c = changeset.CastCoerce[Query](map[string]interface{}{"Page": "abc"})
+
+	if err := c.GetError("Page"); c.IsValid || err == nil || err.Error() != "type mismatch: expect int got string" {
+		t.Errorf("CastCoerce should add a type mismatch error on unparsable strings, got: %v", err)
+	}
+}
+
+type Color int
+
+func (c *Color) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "red":
+		*c = 1
+	case "blue":
+		*c = 2
+	default:
+		return fmt.Errorf("unknown color %q", text)
+	}
+	return nil
+}
+
+type Paint struct{ Color Color }
+
+func TestCastTextUnmarshaler(t *testing.T) {
+	c := changeset.Cast[Paint](map[string]interface{}{"Color": "blue"})
+
+	if v, ok := c.GetChange("Color"); !c.IsValid || !ok || v != Color(2) {
+		t.Errorf("Cast should decode strings with UnmarshalText, got: %v", v)
+	}
+
+	c = changeset.Cast[Paint](map[string]interface{}{"Color": "pink"})
+
+	if err := c.GetError("Color"); c.IsValid || err == nil || err.Error() != `is invalid: unknown color "pink"` {
+		t.Errorf("Cast should add error on UnmarshalText failures, got: %v", err)
+	}
+}
+
+type Small struct{ Level int8 }
+
+func TestCastCoerceIntRange(t *testing.T) {
+	c := changeset.CastCoerce[Small](map[string]interface{}{"Level": float64(200)})
+
+	if c.IsValid {
+		t.Errorf("CastCoerce should reject values overflowing the field")
+	}
+
+	if err := c.GetError("Level"); err == nil || err.Error() != "is out of range for int8" {
+		t.Errorf("CastCoerce should return an out of range error, got: %v", err)
+	}
+
+	c = changeset.CastCoerce[Small](map[string]interface{}{"Level": float64(100)})
+
+	if v, ok := c.GetChange("Level"); !c.IsValid || !ok || v != int8(100) {
+		t.Errorf("CastCoerce should convert values fitting the field, got: %v", v)
+	}
+}
+
+func TestValidateIntRange(t *testing.T) {
+	attrs := map[string]interface{}{"A": 200}
+	c := changeset.Cast[R](attrs)
+
+	if c := c.ValidateChange("A", changeset.IntRangeValidator{Kind: reflect.Int8}); c.IsValid {
+		t.Errorf("IntRangeValidator should fail on values overflowing the kind")
+	}
+
+	attrs = map[string]interface{}{"A": 100}
+	c = changeset.Cast[R](attrs)
+
+	if c := c.ValidateChange("A", changeset.IntRangeValidator{Kind: reflect.Int8}); !c.IsValid {
+		t.Errorf("IntRangeValidator should pass on values fitting the kind")
+	}
+}
+
+func TestProblemJSON(t *testing.T) {
+	attrs := map[string]interface{}{"A": 123, "B": "hello"}
+	c := changeset.Cast[T](attrs)
+
+	p := c.ProblemJSON("https://example.com/probs/invalid", "Invalid params")
+
+	if p["type"] != "https://example.com/probs/invalid" || p["title"] != "Invalid params" || p["status"] != 422 {
+		t.Errorf("ProblemJSON should set the problem members, got: %v", p)
+	}
+
+	expected := []map[string]string{
+		{"name": "A", "reason": "type mismatch: expect string got int"},
+		{"name": "B", "reason": "type mismatch: expect int got string"},
+	}
+
+	if params := p["invalid-params"]; !reflect.DeepEqual(params, expected) {
+		t.Errorf("ProblemJSON should list every field error, got: %v", params)
+	}
+}
+
+func TestValidateUTF8(t *testing.T) {
+	attrs := map[string]interface{}{"A": "olá, mundo"}
+	c := changeset.Cast[T](attrs)
+
+	if c := c.ValidateChange("A", changeset.UTF8Validator{}); !c.IsValid {
+		t.Errorf("UTF8Validator shouldn't add error on valid strings")
+	}
+
+	attrs = map[string]interface{}{"A": "hello\xff"}
+	c = changeset.Cast[T](attrs)
+
+	if c := c.ValidateChange("A", changeset.UTF8Validator{}); c.IsValid {
+		t.Errorf("UTF8Validator should add error on invalid byte sequences")
+	}
+}
+
+type Split struct{ X, Y, Z int }
+
+type SumValidator struct{ Total int }
+
+func (sv SumValidator) ValidateChangeset(c *changeset.Changeset[Split]) []changeset.FieldError {
+	var sum int
+	for _, field := range []string{"X", "Y", "Z"} {
+		v, _ := c.GetChange(field)
+		n, _ := v.(int)
+		sum += n
+	}
+
+	if sum != sv.Total {
+		return []changeset.FieldError{{Field: "Z", Err: fmt.Errorf("must sum up to %d", sv.Total)}}
+	}
+
+	return nil
+}
+
+func TestValidateWith(t *testing.T) {
+	attrs := map[string]interface{}{"X": 50, "Y": 30, "Z": 20}
+	c := changeset.Cast[Split](attrs)
+
+	if c := c.ValidateWith(SumValidator{Total: 100}); !c.IsValid {
+		t.Errorf("ValidateWith shouldn't add errors when the validator passes")
+	}
+
+	attrs = map[string]interface{}{"X": 50, "Y": 30, "Z": 10}
+	c = changeset.Cast[Split](attrs).ValidateWith(SumValidator{Total: 100})
+
+	if c.IsValid {
+		t.Errorf("ValidateWith should mark the changeset as invalid when the validator fails")
+	}
+
+	if err := c.GetError("Z"); err == nil || err.Error() != "must sum up to 100" {
+		t.Errorf("ValidateWith should add the returned field errors, got: %v", err)
+	}
+}
+
+func TestValidatePresence(t *testing.T) {
+	for _, blank := range []string{"  ", ""} {
+		c := changeset.Cast[T](map[string]interface{}{"A": blank})
+
+		if c := c.ValidateChange("A", changeset.PresenceValidator{}); c.IsValid {
+			t.Errorf("PresenceValidator should add error on blank strings, got: %q", blank)
+		}
+	}
+
+	c := changeset.Cast[T](map[string]interface{}{"A": "hi"})
+
+	if c := c.ValidateChange("A", changeset.PresenceValidator{}); !c.IsValid {
+		t.Errorf("PresenceValidator shouldn't add error on non blank strings")
+	}
+
+	c2 := changeset.Cast[R](map[string]interface{}{"A": 1})
+
+	if c2 := c2.ValidateChange("A", changeset.PresenceValidator{}); c2.IsValid {
+		t.Errorf("PresenceValidator should add error on non string values")
+	}
+
+	if c2 := c2.ValidateChange("A", changeset.PresenceValidator{AllowNonString: true}); !c2.IsValid {
+		t.Errorf("PresenceValidator should allow non string values when configured")
+	}
+}
+
+func TestValidateParity(t *testing.T) {
+	c := changeset.Cast[R](map[string]interface{}{"A": 4})
+
+	if c := c.ValidateChange("A", changeset.ParityValidator[int]{Even: true}); !c.IsValid {
+		t.Errorf("ParityValidator shouldn't add error on even values when Even is set")
+	}
+
+	c = changeset.Cast[R](map[string]interface{}{"A": 3})
+
+	if c := c.ValidateChange("A", changeset.ParityValidator[int]{Even: true}); c.IsValid {
+		t.Errorf("ParityValidator should add error on odd values when Even is set")
+	}
+
+	if ok, _ := (changeset.ParityValidator[int]{}).Validate("A", 2.0); ok {
+		t.Errorf("ParityValidator should fail on non integer values")
+	}
+
+	if ok, _ := (changeset.ParityValidator[int]{Even: true}).Validate("A", 4.0); ok {
+		t.Errorf("ParityValidator should fail on whole float values")
+	}
+
+	if ok, err := (changeset.ParityValidator[int]{Even: true}).Validate("A", int64(4)); !ok {
+		t.Errorf("ParityValidator should accept other integer types, got: %v", err)
+	}
+}
+
+func TestApplyRules(t *testing.T) {
+	config := []byte(`{
+		"A": {"required": true, "min_length": 1, "max_length": 5, "pattern": "^h"},
+		"B": {"required": true}
+	}`)
+
+	var rules changeset.RulesConfig
+	if err := json.Unmarshal(config, &rules); err != nil {
+		t.Fatalf("RulesConfig should be loaded from JSON, got: %v", err)
+	}
+
+	c := changeset.ApplyRules(changeset.Cast[T](map[string]interface{}{"A": "hello"}), rules)
+
+	if c.IsValid {
+		t.Errorf("ApplyRules should mark the changeset as invalid")
+	}
+
+	if err := c.GetError("A"); err != nil {
+		t.Errorf("ApplyRules shouldn't add error on fields following the rules, got: %v", err)
+	}
+
+	if err := c.GetError("B"); err == nil || err.Error() != "is required" {
+		t.Errorf("ApplyRules should add error on missing required fields, got: %v", err)
+	}
+
+	c = changeset.ApplyRules(changeset.Cast[T](map[string]interface{}{"A": "hello world", "B": 1}), rules)
+
+	if err := c.GetError("A"); err == nil || err.Error() != "should be at most 5 characters" {
+		t.Errorf("ApplyRules should add error on fields breaking the rules, got: %v", err)
+	}
+
+	var broken changeset.RulesConfig
+	if err := json.Unmarshal([]byte(`{"A": {"pattern": "("}}`), &broken); err != nil {
+		t.Fatalf("RulesConfig should be loaded from JSON, got: %v", err)
+	}
+
+	c = changeset.ApplyRules(changeset.Cast[T](map[string]interface{}{"A": "hello"}), broken)
+
+	var ve *changeset.ValidationError
+	if err := c.GetError("A"); c.IsValid || !errors.As(err, &ve) || ve.Key != "rule.pattern" || ve.Params["pattern"] != "(" {
+		t.Errorf("ApplyRules should add a keyed error on invalid pattern rules, got: %#v", err)
+	}
+}
+
+func TestValidatePercentage(t *testing.T) {
+	pv := changeset.PercentageValidator[float64]{}
+
+	if ok, err := pv.Validate("A", 50.0); !ok {
+		t.Errorf("PercentageValidator should pass on values between 0 and 100, got: %v", err)
+	}
+
+	if ok, err := pv.Validate("A", 150.0); ok || err.Error() != "must be between 0 and 100" {
+		t.Errorf("PercentageValidator should fail on values above 100, got: %v", err)
+	}
+
+	if ok, _ := pv.Validate("A", 12.5); ok {
+		t.Errorf("PercentageValidator should fail on fractional values unless allowed")
+	}
+
+	pv = changeset.PercentageValidator[float64]{AllowFractional: true}
+
+	if ok, err := pv.Validate("A", 12.5); !ok {
+		t.Errorf("PercentageValidator should pass on fractional values when allowed, got: %v", err)
+	}
+}
+
+func TestValidateHexColor(t *testing.T) {
+	hv := changeset.HexColorValidator{}
+
+	if ok, err := hv.Validate("A", "#ffffff"); !ok {
+		t.Errorf("HexColorValidator should pass on #RRGGBB colors, got: %v", err)
+	}
+
+	if ok, _ := hv.Validate("A", "#fff"); ok {
+		t.Errorf("HexColorValidator should fail on shorthand colors unless allowed")
+	}
+
+	if ok, _ := hv.Validate("A", "#ffffff80"); ok {
+		t.Errorf("HexColorValidator should fail on colors with alpha unless allowed")
+	}
+
+	hv = changeset.HexColorValidator{AllowShorthand: true}
+
+	if ok, err := hv.Validate("A", "#fff"); !ok {
+		t.Errorf("HexColorValidator should pass on shorthand colors when allowed, got: %v", err)
+	}
+
+	if ok, err := hv.Validate("A", "#zzz"); ok || err.Error() != "is not a valid hex color" {
+		t.Errorf("HexColorValidator should fail on non hex digits, got: %v", err)
+	}
+
+	if ok, _ := hv.Validate("A", 0xffffff); ok {
+		t.Errorf("HexColorValidator should fail on non string values")
+	}
+}
+
+func TestValidateInclusionNumericAware(t *testing.T) {
+	allowed := []interface{}{1, 2, 3}
+
+	if ok, _ := (changeset.InclusionValidator{Allowed: allowed}).Validate("A", float64(2)); ok {
+		t.Errorf("InclusionValidator shouldn't match numbers of different types by default")
+	}
+
+	if ok, err := (changeset.InclusionValidator{Allowed: allowed, NumericAware: true}).Validate("A", float64(2)); !ok {
+		t.Errorf("InclusionValidator should match numbers of different types when NumericAware, got: %v", err)
+	}
+
+	if ok, _ := (changeset.InclusionValidator{Allowed: allowed, NumericAware: true}).Validate("A", float64(4)); ok {
+		t.Errorf("InclusionValidator should fail on numbers not allowed when NumericAware")
+	}
+}
+
+func TestValidateCode(t *testing.T) {
+	for _, code := range []string{"US", "BR"} {
+		if ok, err := (changeset.CodeValidator{Set: changeset.CountryCodes}).Validate("A", code); !ok {
+			t.Errorf("CodeValidator should pass on country codes, got: %v", err)
+		}
+	}
+
+	for _, code := range []string{"USD", "BRL"} {
+		if ok, err := (changeset.CodeValidator{Set: changeset.CurrencyCodes}).Validate("A", code); !ok {
+			t.Errorf("CodeValidator should pass on currency codes, got: %v", err)
+		}
+	}
+
+	if ok, err := (changeset.CodeValidator{Set: changeset.CountryCodes}).Validate("A", "USD"); ok || err.Error() != "is not a valid code" {
+		t.Errorf("CodeValidator should fail on codes outside the set, got: %v", err)
+	}
+
+	if ok, _ := (changeset.CodeValidator{}).Validate("A", 1); ok {
+		t.Errorf("CodeValidator should fail on non string values")
+	}
+}
+
+type Search struct {
+	Query string
+	Page  int
+	Tags  []string
+}
+
+func TestChangesToValues(t *testing.T) {
+	attrs := map[string]interface{}{"Query": "go lang", "Page": 2, "Tags": []string{"a", "b"}}
+	values := changeset.Cast[Search](attrs).ChangesToValues()
+
+	if q := values.Encode(); q != "Page=2&Query=go+lang&Tags=a&Tags=b" {
+		t.Errorf("ChangesToValues should encode every change, got: %s", q)
+	}
+
+	c := changeset.CastQuery[Person](url.Values{"first_name": {"bob"}, "last_name": {"doe"}}).
+		PutChange("Secret", "s3cr3t")
+
+	if q := c.ChangesToValues().Encode(); q != "first_name=bob&last_name=doe" {
+		t.Errorf("ChangesToValues should key changes by param name and skip fields tagged \"-\", got: %s", q)
+	}
+}
+
+func TestValidateMaxEntries(t *testing.T) {
+	mv := changeset.MaxEntriesValidator{Max: 2}
+
+	if ok, err := mv.Validate("A", map[string]string{"a": "1", "b": "2"}); !ok {
+		t.Errorf("MaxEntriesValidator should pass on maps at the limit, got: %v", err)
+	}
+
+	if ok, err := mv.Validate("A", map[string]string{"a": "1", "b": "2", "c": "3"}); ok || err.Error() != "has too many entries" {
+		t.Errorf("MaxEntriesValidator should fail on maps over the limit, got: %v", err)
+	}
+
+	if ok, _ := mv.Validate("A", []string{"a"}); ok {
+		t.Errorf("MaxEntriesValidator should fail on non map values")
+	}
+}
+
+type KindValidator struct{}
+
+func (kv KindValidator) Validate(field string, val interface{}) (bool, error) {
+	return false, errors.New("should be called typed")
+}
+
+func (kv KindValidator) ValidateTyped(field string, val interface{}, fieldType reflect.Type) (bool, error) {
+	switch fieldType.Kind() {
+	case reflect.String:
+		return val != "", errors.New("can't be empty")
+	case reflect.Int:
+		return val.(int) > 0, errors.New("must be positive")
+	}
+
+	return false, errors.New("unsupported kind")
+}
+
+func TestValidateChangeTyped(t *testing.T) {
+	attrs := map[string]interface{}{"A": "hello", "B": 0}
+	c := changeset.Cast[T](attrs).ValidateChange("A", KindValidator{}).ValidateChange("B", KindValidator{})
+
+	if err := c.GetError("A"); err != nil {
+		t.Errorf("ValidateChange should pass the string field type, got: %v", err)
+	}
+
+	if err := c.GetError("B"); err == nil || err.Error() != "must be positive" {
+		t.Errorf("ValidateChange should pass the int field type, got: %v", err)
+	}
+}
+
+func TestValidateAge(t *testing.T) {
+	now := func() time.Time { return time.Date(2024, time.June, 15, 12, 0, 0, 0, time.UTC) }
+	av := changeset.AgeValidator{MinYears: 18, Now: now}
+
+	if ok, err := av.Validate("A", "2006-06-15"); !ok {
+		t.Errorf("AgeValidator should pass on someone exactly at the minimum age, got: %v", err)
+	}
+
+	if ok, err := av.Validate("A", "2006-06-16"); ok || err.Error() != "must be at least 18 years old" {
+		t.Errorf("AgeValidator should fail on someone under the minimum age, got: %v", err)
+	}
+
+	if ok, err := av.Validate("A", "15/06/2006"); ok || err.Error() != "is not a valid date" {
+		t.Errorf("AgeValidator should fail on malformed dates, got: %v", err)
+	}
+}
+
+func TestValidateLengthMultipleOf(t *testing.T) {
+	lv := changeset.LengthMultipleOfValidator{Divisor: 2}
+
+	if ok, err := lv.Validate("A", []float64{1, 2, 3, 4}); !ok {
+		t.Errorf("LengthMultipleOfValidator should pass on multiple lengths, got: %v", err)
+	}
+
+	if ok, err := lv.Validate("A", []float64{1, 2, 3, 4, 5}); ok || err.Error() != "must have a multiple of 2 items" {
+		t.Errorf("LengthMultipleOfValidator should fail on other lengths, got: %v", err)
+	}
+
+	if ok, _ := lv.Validate("A", "ab"); ok {
+		t.Errorf("LengthMultipleOfValidator should fail on non slice values")
+	}
+}
+
+func TestValidateWordCount(t *testing.T) {
+	wv := changeset.WordCountValidator{Min: 1, Max: 2}
+
+	if ok, err := wv.Validate("A", "one two three"); ok || err.Error() != "must have between 1 and 2 words" {
+		t.Errorf("WordCountValidator should fail on too many words, got: %v", err)
+	}
+
+	if ok, err := wv.Validate("A", "  one   two "); !ok {
+		t.Errorf("WordCountValidator should pass on word counts within bounds, got: %v", err)
+	}
+
+	if ok, _ := wv.Validate("A", 1); ok {
+		t.Errorf("WordCountValidator should fail on non string values")
+	}
+}
+
+func TestValidateRange(t *testing.T) {
+	rv := changeset.RangeValidator[int]{Min: 1, Max: 10}
+
+	if ok, err := rv.Validate("A", 0); ok || err.Error() != "must be between 1 and 10" {
+		t.Errorf("RangeValidator should fail on values below Min, got: %v", err)
+	}
+
+	if ok, _ := rv.Validate("A", 11); ok {
+		t.Errorf("RangeValidator should fail on values above Max")
+	}
+
+	if ok, err := rv.Validate("A", 10); !ok {
+		t.Errorf("RangeValidator should pass on values within bounds, got: %v", err)
+	}
+
+	if ok, _ := rv.Validate("A", "5"); ok {
+		t.Errorf("RangeValidator should fail on non Number values")
+	}
+
+	fv := changeset.RangeValidator[float32]{Min: 0.5, Max: 1.5}
+
+	if ok, _ := fv.Validate("A", float32(0.4)); ok {
+		t.Errorf("RangeValidator should fail on float values below Min")
+	}
+
+	if ok, _ := fv.Validate("A", float32(1.6)); ok {
+		t.Errorf("RangeValidator should fail on float values above Max")
+	}
+
+	if ok, err := fv.Validate("A", float32(1)); !ok {
+		t.Errorf("RangeValidator should pass on float values within bounds, got: %v", err)
+	}
+}
+
+type Event struct {
+	DateFormat string
+	Date       string
+}
+
+func TestValidateDateLayoutFunc(t *testing.T) {
+	dv := changeset.DateValidator[Event]{
+		LayoutFunc: func(c *changeset.Changeset[Event]) string {
+			if format, _ := c.GetChange("DateFormat"); format == "br" {
+				return "02/01/2006"
+			}
+			return time.DateOnly
+		},
+	}
+
+	attrs := map[string]interface{}{"DateFormat": "br", "Date": "31/12/2024"}
+	c := changeset.Cast[Event](attrs).ValidateChange("Date", dv)
+
+	if !c.IsValid {
+		t.Errorf("DateValidator should use the layout chosen by LayoutFunc, got: %v", c.GetErrors())
+	}
+
+	attrs = map[string]interface{}{"DateFormat": "iso", "Date": "31/12/2024"}
+	c = changeset.Cast[Event](attrs).ValidateChange("Date", dv)
+
+	if err := c.GetError("Date"); c.IsValid || err == nil || err.Error() != "is not a valid date" {
+		t.Errorf("DateValidator should fail on dates not matching the chosen layout, got: %v", err)
+	}
+}
+
+func TestValidateFlags(t *testing.T) {
+	fv := changeset.FlagsValidator[uint8]{Mask: 0b0111}
+
+	if ok, err := fv.Validate("A", uint8(0b0101)); !ok {
+		t.Errorf("FlagsValidator should pass on values within the mask, got: %v", err)
+	}
+
+	if ok, err := fv.Validate("A", uint8(0b1001)); ok || err.Error() != "contains unknown flags" {
+		t.Errorf("FlagsValidator should fail on values with bits outside the mask, got: %v", err)
+	}
+
+	if ok, _ := fv.Validate("A", 1.0); ok {
+		t.Errorf("FlagsValidator should fail on non integer values")
+	}
+
+	if ok, err := fv.Validate("A", int64(0b0011)); !ok {
+		t.Errorf("FlagsValidator should accept other integer types, got: %v", err)
+	}
+
+	if ok, _ := fv.Validate("A", -1); ok {
+		t.Errorf("FlagsValidator should fail on values out of range of its type")
+	}
+}
+
+func TestValidateFilePath(t *testing.T) {
+	fv := changeset.FilePathValidator{AllowedExtensions: []string{".png", "jpg"}, DisallowTraversal: true}
+
+	if ok, err := fv.Validate("A", "uploads/avatar.PNG"); !ok {
+		t.Errorf("FilePathValidator should pass on allowed extensions, got: %v", err)
+	}
+
+	if ok, err := fv.Validate("A", "uploads/virus.exe"); ok || err.Error() != "has an invalid extension" {
+		t.Errorf("FilePathValidator should fail on disallowed extensions, got: %v", err)
+	}
+
+	if ok, err := fv.Validate("A", "../etc/passwd"); ok || err.Error() != "must not traverse directories" {
+		t.Errorf("FilePathValidator should fail on traversal attempts, got: %v", err)
+	}
+
+	if ok, _ := fv.Validate("A", 1); ok {
+		t.Errorf("FilePathValidator should fail on non string values")
+	}
+}
+
+func TestValidateAll(t *testing.T) {
+	av := changeset.AllValidator{
+		Predicate: func(v interface{}) bool { return v.(int) > 0 },
+		Message:   "must be positive",
+	}
+
+	if ok, err := av.Validate("A", []int{1, 2, 3}); !ok {
+		t.Errorf("AllValidator should pass when every element satisfies the predicate, got: %v", err)
+	}
+
+	if ok, err := av.Validate("A", []int{1, -2, -3}); ok || err.Error() != "must be positive at index 1" {
+		t.Errorf("AllValidator should fail on the first offending element, got: %v", err)
+	}
+
+	if ok, _ := av.Validate("A", 1); ok {
+		t.Errorf("AllValidator should fail on non slice values")
+	}
+}
+
+func TestValidateSubset(t *testing.T) {
+	sv := changeset.SubsetValidator{Allowed: []interface{}{"admin", "member", "guest"}}
+
+	if ok, err := sv.Validate("A", []string{"admin", "guest"}); !ok {
+		t.Errorf("SubsetValidator should pass on fully contained slices, got: %v", err)
+	}
+
+	if ok, err := sv.Validate("A", []string{"admin", "root"}); ok || err.Error() != "has an invalid entry" {
+		t.Errorf("SubsetValidator should fail on slices with disallowed entries, got: %v", err)
+	}
+
+	if ok, _ := sv.Validate("A", "admin"); ok {
+		t.Errorf("SubsetValidator should fail on non slice values")
+	}
+}
+
+func TestValidateStep(t *testing.T) {
+	sv := changeset.StepValidator[int]{Start: 0, Step: 5}
+
+	if ok, err := sv.Validate("A", 10); !ok {
+		t.Errorf("StepValidator should pass on aligned values, got: %v", err)
+	}
+
+	if ok, err := sv.Validate("A", 12); ok || err.Error() != "must align to step" {
+		t.Errorf("StepValidator should fail on unaligned values, got: %v", err)
+	}
+
+	fv := changeset.StepValidator[float64]{Start: 0, Step: 0.1}
+
+	if ok, err := fv.Validate("A", 0.3); !ok {
+		t.Errorf("StepValidator should tolerate float rounding, got: %v", err)
+	}
+
+	if ok, _ := sv.Validate("A", "10"); ok {
+		t.Errorf("StepValidator should fail on non Number values")
+	}
+}
+
+func TestValidateNotBlank(t *testing.T) {
+	nv := changeset.NotBlankValidator{}
+
+	if ok, err := nv.Validate("A", "   "); ok || err.Error() != "can't be blank" {
+		t.Errorf("NotBlankValidator should fail on whitespace only strings, got: %v", err)
+	}
+
+	if ok, _ := nv.Validate("A", ""); ok {
+		t.Errorf("NotBlankValidator should fail on empty strings")
+	}
+
+	if ok, err := nv.Validate("A", " hi "); !ok {
+		t.Errorf("NotBlankValidator should pass on non blank strings, got: %v", err)
+	}
+
+	if ok, _ := nv.Validate("A", 1); ok {
+		t.Errorf("NotBlankValidator should fail on non string values")
+	}
+}
+
+func TestValidateContains(t *testing.T) {
+	cv := changeset.ContainsValidator{Element: "member"}
+
+	if ok, err := cv.Validate("A", []string{"admin", "member"}); !ok {
+		t.Errorf("ContainsValidator should pass on slices including the element, got: %v", err)
+	}
+
+	if ok, err := cv.Validate("A", []string{"admin"}); ok || err.Error() != "must include the required value" {
+		t.Errorf("ContainsValidator should fail on slices missing the element, got: %v", err)
+	}
+
+	if ok, _ := cv.Validate("A", "member"); ok {
+		t.Errorf("ContainsValidator should fail on non slice values")
+	}
+}
+
+func TestValidateEntropy(t *testing.T) {
+	ev := changeset.EntropyValidator{MinBits: 60}
+
+	if ok, err := ev.Validate("A", "q8#Zr2!vLm@x7Tp$Kd4w"); !ok {
+		t.Errorf("EntropyValidator should pass on random strings, got: %v", err)
+	}
+
+	if ok, err := ev.Validate("A", "aaaaaaaaaaaaaaaaaaaaaaab"); ok || err.Error() != "is too predictable" {
+		t.Errorf("EntropyValidator should fail on repetitive strings, got: %v", err)
+	}
+
+	if ok, _ := ev.Validate("A", 42); ok {
+		t.Errorf("EntropyValidator should fail on non string values")
+	}
+}
+
+func TestMerge(t *testing.T) {
+	a := changeset.Cast[T](map[string]interface{}{"A": "hello", "B": 1})
+	b := changeset.Cast[T](map[string]interface{}{"A": "oi"})
+
+	c := changeset.Merge(a, b)
+
+	if v, _ := c.GetChange("A"); v != "oi" {
+		t.Errorf("Merge should overwrite changes with the second changeset ones, got: %v", v)
+	}
+
+	if v, _ := c.GetChange("B"); v != 1 {
+		t.Errorf("Merge should keep the first changeset changes, got: %v", v)
+	}
+
+	if !c.IsValid {
+		t.Errorf("Merge should be valid when both changesets are valid")
+	}
+
+	a = a.AddDBError("A", errors.New("has already been taken"))
+	b = b.ValidateChange("A", changeset.LengthValidator{Min: 3, Max: 5})
+
+	c = changeset.Merge(a, b)
+
+	if c.IsValid {
+		t.Errorf("Merge should be invalid when any changeset is invalid")
+	}
+
+	if errs := c.GetFieldErrors("A"); len(errs) != 2 {
+		t.Errorf("Merge should keep the errors of both changesets, got: %v", errs)
+	}
+
+	if errs := a.GetFieldErrors("A"); len(errs) != 1 {
+		t.Errorf("Merge should not modify the merged changesets, got: %v", errs)
+	}
+
+	a = changeset.Cast[T](map[string]interface{}{"A": "hello"})
+	b = changeset.Cast[T](map[string]interface{}{"B": 1})
+
+	var s T
+	if err := changeset.Apply(&s, changeset.Merge(a, b)); err != nil {
+		t.Errorf("Apply should apply merged changesets, got: %v", err)
+	}
+
+	if a.IsApplied() || b.IsApplied() {
+		t.Errorf("applying a merged changeset shouldn't mark the merged ones as applied")
+	}
+
+	if err := changeset.Apply(&s, a); err != nil {
+		t.Errorf("Apply should apply changesets merged before, got: %v", err)
+	}
+}
+
+func TestApplyWithReport(t *testing.T) {
+	lv := changeset.LengthValidator{Min: 1, Max: 10}
+	c := changeset.Cast[T](map[string]interface{}{"A": "hello"}).ValidateChange("A", lv)
+
+	s, report, err := changeset.ApplyWithReport(c)
+
+	if err != nil || s.A != "hello" {
+		t.Errorf("ApplyWithReport should apply valid changesets, got: %v %v", s, err)
+	}
+
+	if !reflect.DeepEqual(report.Applied, []string{"A"}) || len(report.Skipped) != 0 {
+		t.Errorf("ApplyWithReport should report the applied fields, got: %v %v", report.Applied, report.Skipped)
+	}
+
+	if v := report.Validators["A"]; v != lv {
+		t.Errorf("ApplyWithReport should report the validators run, got: %v", report.Validators)
+	}
+
+	c = changeset.Cast[T](map[string]interface{}{"A": "hello"}).ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	if _, report, err = changeset.ApplyWithReport(c); err == nil || !reflect.DeepEqual(report.Skipped, []string{"A"}) {
+		t.Errorf("ApplyWithReport should skip every change on invalid changesets, got: %v %v", report.Skipped, err)
+	}
+}
+
+func TestChangesAsStruct(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "hello", "B": 2}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	if data := c.GetData(); data != (T{}) {
+		t.Errorf("GetData should return the original data, got: %v", data)
+	}
+
+	if s := c.ChangesAsStruct(); s != (T{B: 2}) {
+		t.Errorf("ChangesAsStruct should set only the valid changes, got: %v", s)
+	}
+
+	if c.IsApplied() {
+		t.Errorf("ChangesAsStruct should not mark the changeset as applied")
+	}
+}
+
+func TestValidateWithinTolerance(t *testing.T) {
+	c := changeset.Cast[Split](map[string]interface{}{"X": 104, "Y": 100}).
+		ValidateWithinTolerance("X", "Y", 0.05)
+
+	if !c.IsValid {
+		t.Errorf("ValidateWithinTolerance should pass on values within the tolerance, got: %v", c.GetErrors())
+	}
+
+	c = changeset.Cast[Split](map[string]interface{}{"X": 106, "Y": 100}).
+		ValidateWithinTolerance("X", "Y", 0.05)
+
+	if err := c.GetError("X"); c.IsValid || err.Error() != "must be within 5% of Y" {
+		t.Errorf("ValidateWithinTolerance should fail on values outside the tolerance, got: %v", err)
+	}
+
+	c = changeset.Cast[Split](map[string]interface{}{"X": 1, "Y": 0}).
+		ValidateWithinTolerance("X", "Y", 0.05)
+
+	if c.IsValid {
+		t.Errorf("ValidateWithinTolerance should only accept zero on a zero reference")
+	}
+}
+
+func TestMapStringChanges(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "  hello ", "B": 2}).
+		MapStringChanges(strings.TrimSpace)
+
+	if a, _ := c.GetChange("A"); a != "hello" {
+		t.Errorf("MapStringChanges should transform string changes, got: %q", a)
+	}
+
+	if b, _ := c.GetChange("B"); b != 2 {
+		t.Errorf("MapStringChanges should keep non string changes, got: %v", b)
+	}
+}
+
+type Nullable struct {
+	Nickname *string
+	Meta     interface{}
+	Age      int
+}
+
+func TestCastNil(t *testing.T) {
+	c := changeset.Cast[Nullable](map[string]interface{}{"Nickname": nil, "Meta": nil, "Age": nil})
+
+	if !c.IsValid {
+		t.Errorf("Cast should accept nil params, got: %v", c.GetErrors())
+	}
+
+	if nick, ok := c.GetChange("Nickname"); !ok || nick != (*string)(nil) {
+		t.Errorf("Cast should stage nil on pointer fields, got: %v", nick)
+	}
+
+	if _, ok := c.GetChange("Age"); ok {
+		t.Errorf("Cast should skip nil on non pointer fields")
+	}
+
+	name := "bob"
+	s := Nullable{Nickname: &name, Meta: 1, Age: 3}
+	if err := changeset.Apply(&s, c); err != nil || s.Nickname != nil || s.Meta != nil || s.Age != 3 {
+		t.Errorf("Apply should set nil changes, got: %v %v", s, err)
+	}
+}
+
+func TestValidateUUID(t *testing.T) {
+	uv := changeset.UUIDValidator{}
+
+	if ok, err := uv.Validate("A", "9B2E4F1A-7C3D-4E5F-8A9B-0C1D2E3F4A5B"); !ok {
+		t.Errorf("UUIDValidator should pass on canonical UUIDs, got: %v", err)
+	}
+
+	if ok, err := uv.Validate("A", "9b2e4f1a-7c3d-4e5f-8a9b-0c1d2e3f4a5"); ok || err.Error() != "is not a valid UUID" {
+		t.Errorf("UUIDValidator should fail on malformed UUIDs, got: %v", err)
+	}
+
+	if ok, _ := uv.Validate("A", 42); ok {
+		t.Errorf("UUIDValidator should fail on non string values")
+	}
+
+	uv = changeset.UUIDValidator{Version: 7}
+
+	if ok, err := uv.Validate("A", "9b2e4f1a-7c3d-4e5f-8a9b-0c1d2e3f4a5b"); ok || err.Error() != "is not a version 7 UUID" {
+		t.Errorf("UUIDValidator should fail on version mismatches, got: %v", err)
+	}
+}
+
+func TestValidateMAC(t *testing.T) {
+	mv := changeset.MACValidator{}
+
+	if ok, err := mv.Validate("A", "00:1a:2b:3c:4d:5e"); !ok {
+		t.Errorf("MACValidator should pass on colon separated addresses, got: %v", err)
+	}
+
+	if ok, err := mv.Validate("A", "00-1A-2B-3C-4D-5E-6F-70"); !ok {
+		t.Errorf("MACValidator should pass on hyphen separated addresses, got: %v", err)
+	}
+
+	if ok, err := mv.Validate("A", "00:1a:2b:3c:4d"); ok || err.Error() != "is not a valid MAC address" {
+		t.Errorf("MACValidator should fail on malformed addresses, got: %v", err)
+	}
+
+	if ok, _ := mv.Validate("A", 42); ok {
+		t.Errorf("MACValidator should fail on non string values")
+	}
+}
+
+type Located struct {
+	*Address
+	Bio string
+}
+
+func TestApplyEmbeddedPointer(t *testing.T) {
+	attrs := map[string]interface{}{"Country": "US", "Bio": "hi"}
+	c := changeset.CastDiff(Located{}, attrs)
+
+	if v, ok := c.GetChange("Country"); !ok || v != "US" {
+		t.Errorf("CastDiff should cast fields of nil embedded pointers, got: %v", v)
+	}
+
+	var p Located
+	if err := changeset.Apply(&p, c); err != nil || p.Address == nil || p.Country != "US" || p.Bio != "hi" {
+		t.Errorf("Apply should allocate nil embedded pointers to set their fields, got: %v %v", p, err)
+	}
+}
+
+func TestCastDiff(t *testing.T) {
+	c := changeset.CastDiff(T{A: "hello", B: 1}, map[string]interface{}{"A": "hello", "B": 2})
+
+	if _, ok := c.GetChange("A"); ok {
+		t.Errorf("CastDiff should drop changes equal to the current data")
+	}
+
+	if b, ok := c.GetChange("B"); !ok || b != 2 {
+		t.Errorf("CastDiff should stage changes that differ from the current data, got: %v", b)
+	}
+}
+
+func TestApplyPointerField(t *testing.T) {
+	c := changeset.Cast[Nullable](map[string]interface{}{}).PutChange("Nickname", "bob")
+
+	if !c.IsValid {
+		t.Errorf("PutChange should accept values of the pointer element type, got: %v", c.GetErrors())
+	}
+
+	var s Nullable
+	if err := changeset.Apply(&s, c); err != nil || s.Nickname == nil || *s.Nickname != "bob" {
+		t.Errorf("Apply should set values on pointer fields, got: %v %v", s.Nickname, err)
+	}
+
+	c = changeset.Cast[Nullable](map[string]interface{}{}).PutChange("Nickname", nil)
+
+	if err := changeset.Apply(&s, c); err != nil || s.Nickname != nil {
+		t.Errorf("Apply should clear pointer fields on nil changes, got: %v %v", s.Nickname, err)
+	}
+
+	c = changeset.Cast[Nullable](map[string]interface{}{}).PutChange("Age", nil)
+
+	if c.IsValid {
+		t.Errorf("PutChange should fail on nil for non pointer fields")
+	}
+}
+
+func TestValidateInSetFunc(t *testing.T) {
+	plans := map[string][]interface{}{"free": {1}, "pro": {1, 5, 10}}
+	iv := changeset.InSetFunc[T]{Allowed: func(c *changeset.Changeset[T]) []interface{} {
+		plan, _ := c.GetChange("A")
+		return plans[plan.(string)]
+	}}
+
+	c := changeset.Cast[T](map[string]interface{}{"A": "pro", "B": 5}).ValidateChange("B", iv)
+
+	if !c.IsValid {
+		t.Errorf("InSetFunc should pass on values of the computed set, got: %v", c.GetErrors())
+	}
+
+	c = changeset.Cast[T](map[string]interface{}{"A": "free", "B": 5}).ValidateChange("B", iv)
+
+	if c.IsValid {
+		t.Errorf("InSetFunc should fail on values out of the computed set")
+	}
+}
+
+func TestValidationErrorKeys(t *testing.T) {
+	_, err := changeset.LengthValidator{Min: 1, Max: 2}.Validate("A", "hello")
+
+	var ve *changeset.ValidationError
+	if !errors.As(err, &ve) || ve.Key != "length.max" || ve.Params["count"] != 2 || ve.Params["kind"] != "characters" {
+		t.Errorf("LengthValidator should return a ValidationError with its key and params, got: %#v", err)
+	}
+
+	if err.Error() != "should be at most 2 characters" {
+		t.Errorf("ValidationError should keep the English message, got: %v", err)
+	}
+
+	c := changeset.Cast[T](map[string]interface{}{"B": 1}).
+		ValidateChange("B", changeset.GreaterThanValidator[int]{MinValue: 3})
+
+	if !errors.As(c.GetError("B"), &ve) || ve.Key != "number.greater_than" || ve.Params["min"] != 3 || ve.Params["value"] != 1 {
+		t.Errorf("GreaterThanValidator should return a ValidationError with its key and params, got: %#v", c.GetError("B"))
+	}
+}
+
+func TestValidationErrorKeysAll(t *testing.T) {
+	cases := []struct {
+		v   changeset.Validator
+		val interface{}
+		key string
+		msg string
+	}{
+		{changeset.UTF8Validator{}, "\xff", "utf8", "contains invalid characters"},
+		{changeset.HexColorValidator{}, "red", "hex_color", "is not a valid hex color"},
+		{changeset.UUIDValidator{}, "nope", "uuid", "is not a valid UUID"},
+		{changeset.MACValidator{}, "nope", "mac", "is not a valid MAC address"},
+		{changeset.CodeValidator{}, "XX", "code", "is not a valid code"},
+		{changeset.MaxEntriesValidator{Max: 0}, map[string]int{"a": 1}, "max_entries", "has too many entries"},
+		{changeset.LengthMultipleOfValidator{Divisor: 2}, []int{1}, "length.multiple_of", "must have a multiple of 2 items"},
+		{changeset.WordCountValidator{Min: 2, Max: 3}, "one", "word_count", "must have between 2 and 3 words"},
+		{changeset.DateValidator[T]{Layout: time.DateOnly}, "nope", "date", "is not a valid date"},
+		{changeset.FilePathValidator{AllowedExtensions: []string{"png"}}, "a.gif", "file_path.extension", "has an invalid extension"},
+		{changeset.EntropyValidator{MinBits: 64}, "aaaa", "entropy", "is too predictable"},
+		{changeset.ParityValidator[int]{Even: true}, 3, "parity.even", "must be even"},
+		{changeset.FlagsValidator[int]{Mask: 1}, 2, "flags", "contains unknown flags"},
+		{changeset.StepValidator[int]{Step: 5}, 3, "number.step", "must align to step"},
+		{changeset.PercentageValidator[int]{}, 101, "number.percentage", "must be between 0 and 100"},
+		{changeset.SubsetValidator{Allowed: []interface{}{"a"}}, []string{"b"}, "subset", "has an invalid entry"},
+		{changeset.ContainsValidator{Element: "a"}, []string{"b"}, "contains", "must include the required value"},
+		{changeset.FormatValidator{Pattern: regexp.MustCompile(`a`)}, 1, "type", "is not a string"},
+		{changeset.LessThanValidator[int]{MaxValue: 1}, "1", "type", "isn't a Number 1"},
+	}
+
+	for _, tc := range cases {
+		_, err := tc.v.Validate("A", tc.val)
+
+		var ve *changeset.ValidationError
+		if !errors.As(err, &ve) || ve.Key != tc.key || ve.Error() != tc.msg {
+			t.Errorf("%T should return a ValidationError keyed %q, got: %#v", tc.v, tc.key, err)
+		}
+	}
+
+	c := changeset.Cast[T](map[string]interface{}{"A": "x"}).
+		ValidateChange("B", changeset.FormatValidator{Pattern: regexp.MustCompile(`a`)}).
+		ValidateConfirmation("A", "Confirmation")
+
+	var ve *changeset.ValidationError
+	if !errors.As(c.GetError("B"), &ve) || ve.Key != "missing" {
+		t.Errorf("ValidateChange should key missing changes as missing, got: %#v", c.GetError("B"))
+	}
+
+	if !errors.As(c.GetError("Confirmation"), &ve) || ve.Key != "confirmation" || ve.Error() != "does not match" {
+		t.Errorf("ValidateConfirmation should key mismatches as confirmation, got: %#v", c.GetError("Confirmation"))
+	}
+}
+
+func TestValidateMultipleOf(t *testing.T) {
+	mv := changeset.MultipleOfValidator[int]{Base: 6}
+
+	if ok, err := mv.Validate("B", 18); !ok {
+		t.Errorf("MultipleOfValidator should pass on exact multiples, got: %v", err)
+	}
+
+	if ok, err := mv.Validate("B", 20); ok || err.Error() != "must be a multiple of 6" {
+		t.Errorf("MultipleOfValidator should fail on non multiples, got: %v", err)
+	}
+
+	if ok, err := (changeset.MultipleOfValidator[float64]{Base: 0.1}).Validate("B", 0.3); !ok {
+		t.Errorf("MultipleOfValidator should tolerate float rounding, got: %v", err)
+	}
+
+	if ok, _ := (changeset.MultipleOfValidator[int]{}).Validate("B", 18); ok {
+		t.Errorf("MultipleOfValidator should fail on a zero base")
+	}
+
+	if ok, _ := mv.Validate("B", "18"); ok {
+		t.Errorf("MultipleOfValidator should fail on non numeric values")
+	}
+}
+
+func TestApplyRevalidated(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "hi", "B": 2}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	c = c.PutChange("A", "hello")
+
+	var s T
+	err := changeset.ApplyRevalidated(&s, c)
+
+	var fe changeset.FieldError
+	if !errors.As(err, &fe) || fe.Field != "A" {
+		t.Errorf("ApplyRevalidated should return the fields failing validation, got: %v", err)
+	}
+
+	if s.A != "" || s.B != 2 {
+		t.Errorf("ApplyRevalidated should only set the fields still valid, got: %v", s)
+	}
+
+	c = changeset.Cast[T](map[string]interface{}{"A": "hi"}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	if err := changeset.ApplyRevalidated(&s, c); err != nil || s.A != "hi" {
+		t.Errorf("ApplyRevalidated should set the changes still valid, got: %v %v", s, err)
+	}
+}
+
+func TestApplyRevalidatedEveryValidator(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "abc"}).
+		ValidateChange("A", changeset.FormatValidator{Pattern: regexp.MustCompile(`^[a-z]+$`)}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 10}).
+		PutChange("A", "123")
+
+	var s T
+	err := changeset.ApplyRevalidated(&s, c)
+
+	var fe changeset.FieldError
+	if !errors.As(err, &fe) || fe.Field != "A" || fe.Err.Error() != "has invalid format" {
+		t.Errorf("ApplyRevalidated should run every validator of a field, got: %v", err)
+	}
+
+	if s.A != "" {
+		t.Errorf("ApplyRevalidated shouldn't set fields failing an earlier validator, got: %v", s)
+	}
+}
+
+func TestFuncValidator(t *testing.T) {
+	var calls []string
+	check := func(field string, val interface{}) (bool, error) {
+		calls = append(calls, field)
+		if val.(int) < 18 {
+			return false, errors.New("must be an adult")
+		}
+		return true, nil
+	}
+
+	c := changeset.Cast[T](map[string]interface{}{"B": 12}).
+		ValidateChange("B", changeset.FuncValidator(check))
+
+	if err := c.GetError("B"); c.IsValid || err.Error() != "must be an adult" {
+		t.Errorf("FuncValidator should delegate to the function, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(calls, []string{"B"}) {
+		t.Errorf("FuncValidator should call the function once, got: %v", calls)
+	}
+
+	if _, ok := c.Validations()["B"].(changeset.FuncValidator); !ok {
+		t.Errorf("FuncValidator should show up in Validations, got: %v", c.Validations())
+	}
+}
+
+func TestValidateE164(t *testing.T) {
+	ev := changeset.E164Validator{}
+
+	if ok, err := ev.Validate("A", "+14155550123"); !ok {
+		t.Errorf("E164Validator should pass on E.164 numbers, got: %v", err)
+	}
+
+	if ok, err := ev.Validate("A", "14155550123"); ok || err.Error() != "is not a valid E.164 phone number" {
+		t.Errorf("E164Validator should fail on numbers without a plus, got: %v", err)
+	}
+
+	var ve *changeset.ValidationError
+	if _, err := ev.Validate("A", "nope"); !errors.As(err, &ve) || ve.Key != "e164" {
+		t.Errorf("E164Validator should return a ValidationError keyed e164, got: %#v", err)
+	}
+
+	if ok, _ := ev.Validate("A", "+1415555012345678"); ok {
+		t.Errorf("E164Validator should fail on numbers longer than 15 digits")
+	}
+
+	if ok, _ := ev.Validate("A", 14155550123); ok {
+		t.Errorf("E164Validator should fail on non string values")
+	}
+}
+
+func TestValidateNumberKinds(t *testing.T) {
+	gtv := changeset.GreaterThanValidator[int]{MinValue: 3}
+
+	if ok, err := gtv.Validate("B", int64(5)); !ok {
+		t.Errorf("GreaterThanValidator should compare other numeric kinds, got: %v", err)
+	}
+
+	if ok, err := gtv.Validate("B", int64(1)); ok || err.Error() != "must be greater than 1" {
+		t.Errorf("GreaterThanValidator should fail on smaller values of other numeric kinds, got: %v", err)
+	}
+
+	if ok, err := gtv.Validate("B", 4.5); ok || err.Error() != "isn't a Number 4.5" {
+		t.Errorf("GreaterThanValidator should fail on values losing precision, got: %v", err)
+	}
+
+	if ok, _ := (changeset.RangeValidator[int8]{Min: 0, Max: 10}).Validate("B", 300); ok {
+		t.Errorf("RangeValidator should fail on values out of the type range")
+	}
+}
+
+type Item struct{ Name string }
+
+type Order struct{ Items []Item }
+
+func TestGetEmbedSlice(t *testing.T) {
+	items := []Item{{Name: "pen"}, {Name: "ink"}}
+	c := changeset.Cast[Order](map[string]interface{}{"Items": items}).
+		ValidateChange("Items", changeset.LengthValidator{Min: 1, Max: 5})
+
+	if got, ok := changeset.GetEmbedSlice[Item](c, "Items"); !c.IsValid || !ok || !reflect.DeepEqual(got, items) {
+		t.Errorf("GetEmbedSlice should return the typed slice change, got: %v", got)
+	}
+
+	if _, ok := changeset.GetEmbedSlice[string](c, "Items"); ok {
+		t.Errorf("GetEmbedSlice should fail on slices of another type")
+	}
+}
+
+func TestApplyMap(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "hello", "C": true})
+
+	if m, err := changeset.ApplyMap(c); err != nil || !reflect.DeepEqual(m, map[string]interface{}{"A": "hello"}) {
+		t.Errorf("ApplyMap should return the changes, got: %v %v", m, err)
+	}
+
+	c = c.ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	if m, err := changeset.ApplyMap(c); err == nil || m != nil {
+		t.Errorf("ApplyMap should return the changeset as error when invalid, got: %v", m)
+	}
+}
+
+func TestValidateProbability(t *testing.T) {
+	pv := changeset.ProbabilityValidator{}
+
+	if ok, err := pv.Validate("A", 0.5); !ok {
+		t.Errorf("ProbabilityValidator should pass on values between 0 and 1, got: %v", err)
+	}
+
+	if ok, err := pv.Validate("A", 1.5); ok || err.Error() != "must be between 0 and 1" {
+		t.Errorf("ProbabilityValidator should fail on values above 1, got: %v", err)
+	}
+
+	var ve *changeset.ValidationError
+	if _, err := pv.Validate("A", 1.5); !errors.As(err, &ve) || ve.Key != "number.probability" || ve.Params["value"] != 1.5 {
+		t.Errorf("ProbabilityValidator should return a ValidationError keyed number.probability, got: %#v", err)
+	}
+
+	if ok, _ := pv.Validate("A", float32(-0.1)); ok {
+		t.Errorf("ProbabilityValidator should fail on negative values")
+	}
+
+	if ok, _ := pv.Validate("A", 1); ok {
+		t.Errorf("ProbabilityValidator should fail on non float values")
+	}
+}
+
+type Filter struct {
+	Tags  []string `json:"tag"`
+	IDs   []int    `json:"id"`
+	Limit int      `json:"limit"`
+}
+
+func TestCastQuery(t *testing.T) {
+	q := url.Values{"tag": {"a", "b"}, "id": {"1", "2"}, "limit": {"10", "20"}}
+	c := changeset.CastQuery[Filter](q)
+
+	if !c.IsValid {
+		t.Errorf("CastQuery should be valid, got: %v", c.GetErrors())
+	}
+
+	if tags, _ := c.GetChange("Tags"); !reflect.DeepEqual(tags, []string{"a", "b"}) {
+		t.Errorf("CastQuery should cast repeated keys into slices, got: %v", tags)
+	}
+
+	if ids, _ := c.GetChange("IDs"); !reflect.DeepEqual(ids, []int{1, 2}) {
+		t.Errorf("CastQuery should coerce slice elements, got: %v", ids)
+	}
+
+	if limit, _ := c.GetChange("Limit"); limit != 10 {
+		t.Errorf("CastQuery should coerce the first value into scalars, got: %v", limit)
+	}
+
+	c = changeset.CastQuery[Filter](url.Values{"id": {"1", "x"}})
+
+	if c.IsValid {
+		t.Errorf("CastQuery should fail on elements that can't be coerced")
+	}
+}
+
+func TestValidateInclusionMessage(t *testing.T) {
+	iv := changeset.InclusionValidator{Allowed: []interface{}{"red", "green", 3}}
+
+	if ok, err := iv.Validate("A", "blue"); ok || err.Error() != "must be one of: red, green, 3" {
+		t.Errorf("InclusionValidator should list the allowed values, got: %v", err)
+	}
+}
+
+func TestValidateCron(t *testing.T) {
+	cv := changeset.CronValidator{}
+
+	for _, expr := range []string{"0 0 * * *", "@daily", "*/15 9-17 * jan-jun MON-FRI", "30 0 0 1,15 * 0"} {
+		if ok, err := cv.Validate("A", expr); !ok {
+			t.Errorf("CronValidator should pass on %q, got: %v", expr, err)
+		}
+	}
+
+	for _, expr := range []string{"99 * * * *", "* * *", "*/0 * * * *", "5-1 * * * *", "@often"} {
+		if ok, err := cv.Validate("A", expr); ok || err.Error() != "is not a valid cron expression" {
+			t.Errorf("CronValidator should fail on %q, got: %v", expr, err)
+		}
+	}
+
+	var ve *changeset.ValidationError
+	if _, err := cv.Validate("A", "@often"); !errors.As(err, &ve) || ve.Key != "cron" {
+		t.Errorf("CronValidator should return a ValidationError keyed cron, got: %#v", err)
+	}
+
+	if ok, _ := cv.Validate("A", 0); ok {
+		t.Errorf("CronValidator should fail on non string values")
+	}
+}
+
+func TestValidateEach(t *testing.T) {
+	fv := changeset.FormatValidator{Pattern: regexp.MustCompile(`^[a-z]+$`)}
+	c := changeset.Cast[Tagged](map[string]interface{}{"Tags": []string{"go", "Elixir", "rust"}}).
+		ValidateEach("Tags", fv)
+
+	if c.IsValid || !reflect.DeepEqual(c.ErrorKeys(), []string{"Tags.1"}) {
+		t.Errorf("ValidateEach should add indexed errors on failing elements, got: %v", c.GetErrors())
+	}
+
+	c2 := changeset.Cast[T](map[string]interface{}{"A": "go"}).ValidateEach("A", fv)
+
+	if err := c2.GetError("A"); c2.IsValid || err.Error() != "is not a slice" {
+		t.Errorf("ValidateEach should add a single error on non slice changes, got: %v", err)
+	}
+}
+
+func TestValidateEachRevalidated(t *testing.T) {
+	fv := changeset.FormatValidator{Pattern: regexp.MustCompile(`^[a-z]+$`)}
+	c := changeset.Cast[Tagged](map[string]interface{}{"Tags": []string{"go", "rust"}}).
+		ValidateEach("Tags", fv)
+
+	var s Tagged
+	if err := changeset.ApplyRevalidated(&s, c); err != nil {
+		t.Errorf("ApplyRevalidated shouldn't run element validators on the whole slice, got: %v", err)
+	}
+
+	if !reflect.DeepEqual(s.Tags, []string{"go", "rust"}) {
+		t.Errorf("ApplyRevalidated should apply a valid slice, got: %v", s.Tags)
+	}
+}
+
+func TestEffectiveValid(t *testing.T) {
+	c := changeset.CastFrom(T{A: "hi", B: 1}, map[string]interface{}{"A": "hello", "B": 2}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	if s := c.EffectiveValid(); s != (T{A: "hi", B: 2}) {
+		t.Errorf("EffectiveValid should keep the data on errored fields and the changes on the others, got: %v", s)
+	}
+}
+
+type Shipment struct {
+	Code    string
+	Address Address
+}
+
+func TestCastAssoc(t *testing.T) {
+	castAddress := func(params map[string]interface{}) changeset.Changeset[Address] {
+		return changeset.Cast[Address](params).ValidateRequired([]string{"Country"})
+	}
+
+	c := changeset.Cast[Shipment](map[string]interface{}{"Code": "X1"})
+	c = changeset.CastAssoc(c, "Address", map[string]interface{}{"Country": "BR", "State": "RJ"}, castAddress)
+
+	if a, ok := c.GetChange("Address"); !c.IsValid || !ok || a != (Address{Country: "BR", State: "RJ"}) {
+		t.Errorf("CastAssoc should put the applied child as a change, got: %v %v", a, c.GetErrors())
+	}
+
+	c = changeset.Cast[Shipment](map[string]interface{}{"Code": "X1"})
+	c = changeset.CastAssoc(c, "Address", map[string]interface{}{"State": "RJ"}, castAddress)
+
+	if c.IsValid || c.GetError("Address.Country") == nil {
+		t.Errorf("CastAssoc should add the child errors under namespaced keys, got: %v", c.GetErrors())
+	}
+
+	if _, ok := c.GetChange("Address"); ok {
+		t.Errorf("CastAssoc should not put invalid children as a change")
+	}
+}
+
+func TestPipe(t *testing.T) {
+	steps := []func(changeset.Changeset[T]) changeset.Changeset[T]{
+		func(c changeset.Changeset[T]) changeset.Changeset[T] { return c.PutChange("B", 3) },
+		func(c changeset.Changeset[T]) changeset.Changeset[T] {
+			return c.ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+		},
+		func(c changeset.Changeset[T]) changeset.Changeset[T] { return c.ValidateRequired([]string{"A", "B"}) },
+	}
+
+	for _, attrs := range []map[string]interface{}{{"A": "hi"}, {"A": "hello"}} {
+		piped := changeset.Pipe(changeset.Cast[T](attrs), steps...)
+		chained := changeset.Cast[T](attrs).
+			PutChange("B", 3).
+			ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2}).
+			ValidateRequired([]string{"A", "B"})
+
+		if piped.IsValid != chained.IsValid || !reflect.DeepEqual(piped.GetChanges(), chained.GetChanges()) {
+			t.Errorf("Pipe should match the method chain, got: %v %v", piped.GetErrors(), chained.GetErrors())
+		}
+	}
+}
+
+func TestValidateISBN(t *testing.T) {
+	iv := changeset.ISBNValidator{}
+
+	for _, isbn := range []string{"978-0-306-40615-7", "0-306-40615-2", "0-8044-2957-X"} {
+		if ok, err := iv.Validate("A", isbn); !ok {
+			t.Errorf("ISBNValidator should pass on %q, got: %v", isbn, err)
+		}
+	}
+
+	if ok, err := iv.Validate("A", "978-0-306-40615-8"); ok || err.Error() != "is not a valid ISBN" {
+		t.Errorf("ISBNValidator should fail on bad check digits, got: %v", err)
+	}
+
+	var ve *changeset.ValidationError
+	if _, err := iv.Validate("A", "978-0-306-40615-8"); !errors.As(err, &ve) || ve.Key != "isbn" {
+		t.Errorf("ISBNValidator should return a ValidationError keyed isbn, got: %#v", err)
+	}
+
+	if ok, _ := iv.Validate("A", 9780306406157); ok {
+		t.Errorf("ISBNValidator should fail on non string values")
+	}
+}
+
+func TestValidateIBAN(t *testing.T) {
+	iv := changeset.IBANValidator{}
+
+	if ok, err := iv.Validate("A", "GB82 WEST 1234 5698 7654 32"); !ok {
+		t.Errorf("IBANValidator should pass on valid IBANs, got: %v", err)
+	}
+
+	if ok, err := iv.Validate("A", "GB82 WEST 1234 5698 7654 33"); ok || err.Error() != "is not a valid IBAN" {
+		t.Errorf("IBANValidator should fail on wrong check digits, got: %v", err)
+	}
+
+	var ve *changeset.ValidationError
+	if _, err := iv.Validate("A", "GB82 WEST 1234 5698 7654 33"); !errors.As(err, &ve) || ve.Key != "iban" {
+		t.Errorf("IBANValidator should return a ValidationError keyed iban, got: %#v", err)
+	}
+
+	if ok, _ := iv.Validate("A", 42); ok {
+		t.Errorf("IBANValidator should fail on non string values")
+	}
+}
+
+func TestValidateDateRange(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC) }
+	dv := changeset.DateRangeValidator{Min: day(1), Max: day(10)}
+
+	if ok, err := dv.Validate("A", day(5)); !ok {
+		t.Errorf("DateRangeValidator should pass on dates within the range, got: %v", err)
+	}
+
+	if ok, err := dv.Validate("A", day(1).Add(-time.Second)); ok || err.Error() != "must be on or after 2024-03-01T00:00:00Z" {
+		t.Errorf("DateRangeValidator should fail on dates before Min, got: %v", err)
+	}
+
+	if ok, err := dv.Validate("A", day(11)); ok || err.Error() != "must be on or before 2024-03-10T00:00:00Z" {
+		t.Errorf("DateRangeValidator should fail on dates after Max, got: %v", err)
+	}
+
+	var ve *changeset.ValidationError
+	if _, err := dv.Validate("A", day(11)); !errors.As(err, &ve) || ve.Key != "date_range.max" || !ve.Params["max"].(time.Time).Equal(day(10)) {
+		t.Errorf("DateRangeValidator should return a ValidationError keyed date_range.max, got: %#v", err)
+	}
+
+	if ok, err := (changeset.DateRangeValidator{Min: day(1)}).Validate("A", day(1).AddDate(10, 0, 0)); !ok {
+		t.Errorf("DateRangeValidator should not bound a zero Max, got: %v", err)
+	}
+
+	if ok, _ := dv.Validate("A", "2024-03-05"); ok {
+		t.Errorf("DateRangeValidator should fail on non time values")
+	}
+}
+
+func TestPutChangesFromStruct(t *testing.T) {
+	type Input struct {
+		A string
+		B int
+		D bool
+	}
+
+	c := changeset.Cast[T](map[string]interface{}{}).
+		PutChangesFromStruct(&Input{A: "hello", B: 2}, []string{"A", "B"})
+
+	if !c.IsValid || !reflect.DeepEqual(c.GetChanges(), map[string]interface{}{"A": "hello", "B": 2}) {
+		t.Errorf("PutChangesFromStruct should copy the given fields, got: %v %v", c.GetChanges(), c.GetErrors())
+	}
+
+	c = changeset.Cast[T](map[string]interface{}{}).PutChangesFromStruct(Input{}, []string{"C"})
+
+	if c.IsValid || c.GetError("C") == nil {
+		t.Errorf("PutChangesFromStruct should fail on fields missing on the source")
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	schema := changeset.SchemaValidator{
+		"A": {Type: "string", Required: true},
+		"B": {Type: "integer"},
+	}
+
+	c := changeset.ValidateSchema[T](map[string]interface{}{"A": "hello", "B": 2}, schema)
+
+	if !c.IsValid {
+		t.Errorf("ValidateSchema should pass on params matching the schema, got: %v", c.GetErrors())
+	}
+
+	c = changeset.ValidateSchema[T](map[string]interface{}{"B": "2"}, schema)
+
+	if err := c.GetError("A"); c.IsValid || err == nil || err.Error() != "is required" {
+		t.Errorf("ValidateSchema should fail on missing required params, got: %v", err)
+	}
+
+	if errs := c.GetFieldErrors("B"); len(errs) != 1 || errs[0].Error() != "must be of type integer" {
+		t.Errorf("ValidateSchema should fail on params of the wrong type, got: %v", errs)
+	}
+}
+
+func TestChangesetImmutable(t *testing.T) {
+	original := changeset.Cast[T](map[string]interface{}{"A": "hello"})
+
+	derived := original.
+		PutChange("B", 2).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2}).
+		AddError("B", errors.New("is taken"))
+
+	if derived.IsValid || len(derived.GetErrors()) != 2 {
+		t.Errorf("the derived changeset should hold the new errors, got: %v", derived.GetErrors())
+	}
+
+	if !original.IsValid || len(original.GetErrors()) != 0 || len(original.Validations()) != 0 {
+		t.Errorf("the original changeset should keep its errors and validations, got: %v %v", original.GetErrors(), original.Validations())
+	}
+
+	if _, ok := original.GetChange("B"); ok {
+		t.Errorf("the original changeset should keep its changes")
+	}
+
+	derived = derived.DeleteChange("A").ClearError("B")
+
+	if _, ok := original.GetChange("A"); !ok {
+		t.Errorf("deleting a change on a derived changeset should not affect the original")
+	}
+}
+
+func TestTraverseErrorsTyped(t *testing.T) {
+	c := changeset.Cast[T](map[string]interface{}{"A": "hello"}).
+		ValidateChange("A", changeset.LengthValidator{Min: 1, Max: 2})
+
+	messages := changeset.TraverseErrorsTyped(c, func(_ *changeset.Changeset[T], err error, _ changeset.Validator) string {
+		return err.Error()
+	})
+
+	if !reflect.DeepEqual(messages, map[string]string{"A": "should be at most 2 characters"}) {
+		t.Errorf("TraverseErrorsTyped should return the typed callback results, got: %v", messages)
+	}
+}
+
+func TestCastStruct(t *testing.T) {
+	p := Person{FirstName: "bob", LastName: "doe", Age: 30}
+	c := changeset.CastStruct(p)
+
+	expected := map[string]interface{}{"FirstName": "bob", "LastName": "doe", "Age": 30}
+	if !c.IsValid || !reflect.DeepEqual(c.GetChanges(), expected) {
+		t.Errorf("CastStruct should stage every exported field but the skipped ones, got: %v", c.GetChanges())
+	}
+
+	if s, err := changeset.ApplyNew(c); err != nil || s != p {
+		t.Errorf("ApplyNew should reproduce the original struct, got: %v %v", s, err)
 	}
 }
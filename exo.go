@@ -1,6 +1,10 @@
 package exo
 
-import "reflect"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
 
 func ToMap(s interface{}) map[string]interface{} {
 	out := make(map[string]interface{})
@@ -8,28 +12,308 @@ func ToMap(s interface{}) map[string]interface{} {
 
 	for _, field := range fields {
 		name := field.Name
-		val := toValue(s).FieldByName(name)
+		val := FieldByName(reflect.Indirect(toValue(s)), name)
+		if !val.IsValid() {
+			continue
+		}
 		out[name] = val.Interface()
 	}
 
 	return out
 }
 
-func StructFields(s interface{}) []reflect.StructField {
-	t := toValue(s).Type()
-	f := make([]reflect.StructField, t.NumField())
+// The inverse of ToMap, allocates a new struct and sets each
+// field from the map entry of the same name. Missing keys keep
+// the zero value and values not assignable to their field
+// return an error.
+func FromMap[T interface{}](m map[string]interface{}) (T, error) {
+	var s T
+
+	r := reflect.ValueOf(&s).Elem()
+	if r.Kind() != reflect.Struct {
+		return s, fmt.Errorf("argument to FromMap is not a struct")
+	}
+
+	for _, field := range StructFields(s) {
+		value, ok := m[field.Name]
+		if !ok {
+			continue
+		}
 
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		if field.PkgPath != "" {
+		f := SettableFieldByName(r, field.Name)
+		if !f.CanSet() {
 			continue
 		}
-		f[i] = field
+
+		val := toValue(value)
+		if !val.IsValid() || !val.Type().AssignableTo(f.Type()) {
+			return s, fmt.Errorf("type mismatch on %s: expect %s got %T", field.Name, f.Type(), value)
+		}
+
+		f.Set(val)
+	}
+
+	return s, nil
+}
+
+// Return the exported fields of a struct, or of a pointer to
+// a struct. Fields of embedded structs are promoted following
+// Go's own rules: a shallower field hides the deeper ones with
+// the same name and fields with the same name at the same depth
+// hide each other. Fields of embedded pointers to structs are
+// promoted too, check `FieldByName` to read them safely.
+func StructFields(s interface{}) []reflect.StructField {
+	t := reflect.Indirect(toValue(s)).Type()
+	f := make([]reflect.StructField, 0, t.NumField())
+
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+
+	hidden := make(map[string]bool)
+	visited := map[reflect.Type]bool{t: true}
+	level := []embedded{{typ: t}}
+
+	for len(level) > 0 {
+		var next []embedded
+		var found []reflect.StructField
+		count := make(map[string]int)
+
+		for _, e := range level {
+			for i := 0; i < e.typ.NumField(); i++ {
+				field := e.typ.Field(i)
+				if hidden[field.Name] {
+					continue
+				}
+
+				field.Index = append(append([]int{}, e.index...), i)
+				count[field.Name]++
+
+				typ := field.Type
+				if typ.Kind() == reflect.Ptr {
+					typ = typ.Elem()
+				}
+
+				switch {
+				case field.Anonymous && typ.Kind() == reflect.Struct:
+					if !visited[typ] {
+						next = append(next, embedded{typ: typ, index: field.Index})
+					}
+				case field.PkgPath == "":
+					found = append(found, field)
+				}
+			}
+		}
+
+		for _, field := range found {
+			if count[field.Name] == 1 {
+				f = append(f, field)
+			}
+		}
+
+		for name := range count {
+			hidden[name] = true
+		}
+
+		for _, e := range next {
+			visited[e.typ] = true
+		}
+
+		level = next
 	}
 
 	return f
 }
 
+// Compare two instances of the same struct, or pointers to it,
+// returning the new value of each exported field whose values
+// aren't deep equal, keyed by field name. Useful for audit logs
+// and partial updates.
+func Diff[T interface{}](old, updated T) map[string]interface{} {
+	out := make(map[string]interface{})
+	o := reflect.Indirect(toValue(old))
+	u := reflect.Indirect(toValue(updated))
+
+	for _, field := range StructFields(old) {
+		prev := fieldOrZero(o, field)
+		next := fieldOrZero(u, field)
+
+		if !reflect.DeepEqual(prev, next) {
+			out[field.Name] = next
+		}
+	}
+
+	return out
+}
+
+// Sets each change on the field of the same name of the struct
+// pointed by dst, type checking every value. It's meant for code
+// that only holds the struct as an interface{}, not its type.
+// Changes without a matching exported field are ignored.
+func ApplyToAny(dst interface{}, changes map[string]interface{}) error {
+	v := toValue(dst)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("argument to ApplyToAny is not a pointer to a struct")
+	}
+
+	r := v.Elem()
+	for name, change := range changes {
+		f := SettableFieldByName(r, name)
+		if !(f.IsValid() && f.CanSet()) {
+			continue
+		}
+
+		val := toValue(change)
+		if !val.IsValid() || !val.Type().AssignableTo(f.Type()) {
+			return fmt.Errorf("type mismatch on %s: expect %s got %T", name, f.Type(), change)
+		}
+
+		f.Set(val)
+	}
+
+	return nil
+}
+
+// Same as reflect.Value.FieldByName, but doesn't panic on fields
+// promoted through a nil embedded pointer, returning the zero
+// Value instead, which isn't valid.
+func FieldByName(v reflect.Value, name string) reflect.Value {
+	return fieldByName(v, name, false)
+}
+
+// Same as FieldByName, but allocates the nil embedded pointers on
+// the way to the field so it can be set. The struct value must be
+// addressable, like the element of a pointer to it.
+func SettableFieldByName(v reflect.Value, name string) reflect.Value {
+	return fieldByName(v, name, true)
+}
+
+func fieldByName(v reflect.Value, name string, alloc bool) reflect.Value {
+	sf, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}
+	}
+
+	for i, x := range sf.Index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc || !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+
+	return v
+}
+
+// Return the value of the field, or its zero value when it's
+// promoted through a nil embedded pointer.
+func fieldOrZero(v reflect.Value, field reflect.StructField) interface{} {
+	if f := FieldByName(v, field.Name); f.IsValid() {
+		return f.Interface()
+	}
+
+	return reflect.Zero(field.Type).Interface()
+}
+
+// Return the name a struct field is known by on external data,
+// like parameters maps: its `exo` tag, falling back to the name
+// on its `json` tag and then to the field name itself.
+// A field tagged with "-" returns an empty name.
+func ParamName(field reflect.StructField) string {
+	for _, key := range []string{"exo", "json"} {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
+
+// Deep copies a value, including nested structs, slices, maps,
+// arrays, pointers and interfaces, so the copy doesn't share any
+// backing storage with the original. Useful for snapshotting
+// records before applying changes to them. Unexported struct
+// fields are copied shallowly and cyclic values aren't supported.
+func Clone[T interface{}](v T) T {
+	var out T
+	reflect.ValueOf(&out).Elem().Set(deepCopy(reflect.ValueOf(&v).Elem()))
+	return out
+}
+
+// Copies slices, maps, pointers, arrays, structs and interfaces
+// recursively so the copy doesn't share any backing storage
+// with the original value. Unexported struct fields are copied
+// shallowly.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(deepCopy(iter.Key()), deepCopy(iter.Value()))
+		}
+		return out
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(deepCopy(v.Elem()))
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(deepCopy(v.Elem()))
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if f := out.Field(i); f.CanSet() {
+				f.Set(deepCopy(v.Field(i)))
+			}
+		}
+		return out
+	}
+
+	return v
+}
+
 func toValue(s interface{}) reflect.Value {
 	return reflect.ValueOf(s)
 }
@@ -34,6 +34,42 @@ func TestStructFields(t *testing.T) {
 	}
 }
 
+func TestStructFieldsUnexported(t *testing.T) {
+	var T = struct {
+		A string
+		b int
+		C bool
+	}{A: "hello", b: 2, C: true}
+
+	a := exo.StructFields(T)
+
+	if l := len(a); l != 2 {
+		t.Errorf("StructFields should skip unexported fields, got length: %d", l)
+	}
+
+	for _, f := range a {
+		if f.Name == "" || f.Name == "b" {
+			t.Errorf("StructFields should only return exported fields, got: %q", f.Name)
+		}
+	}
+
+	if m := exo.ToMap(T); len(m) != 2 {
+		t.Errorf("ToMap should skip unexported fields, got: %v", m)
+	}
+}
+
+func TestStructFieldsPointer(t *testing.T) {
+	u := User{Name: "bob", Age: 30}
+
+	if a, b := len(exo.StructFields(u)), len(exo.StructFields(&u)); a != b {
+		t.Errorf("StructFields should return the same fields for a pointer, got: %d and %d", a, b)
+	}
+
+	if a, b := exo.ToMap(u), exo.ToMap(&u); !reflect.DeepEqual(a, b) {
+		t.Errorf("ToMap should return the same map for a pointer, got: %v and %v", a, b)
+	}
+}
+
 func TestMap(t *testing.T) {
 	var T = struct {
 		A string
@@ -56,3 +92,181 @@ func TestMap(t *testing.T) {
 		}
 	}
 }
+
+type User struct {
+	Name string
+	Age  int
+}
+
+func TestApplyToAny(t *testing.T) {
+	var dst interface{} = &User{Name: "old", Age: 30}
+
+	if err := exo.ApplyToAny(dst, map[string]interface{}{"Name": "bob"}); err != nil {
+		t.Errorf("ApplyToAny should apply valid changes, got: %v", err)
+	}
+
+	if u := dst.(*User); u.Name != "bob" || u.Age != 30 {
+		t.Errorf("ApplyToAny should only set the given fields, got: %v", u)
+	}
+
+	if err := exo.ApplyToAny(dst, map[string]interface{}{"Age": "old"}); err == nil {
+		t.Errorf("ApplyToAny should return an error on type mismatch")
+	}
+
+	if err := exo.ApplyToAny(User{}, map[string]interface{}{"Name": "bob"}); err == nil {
+		t.Errorf("ApplyToAny should return an error on non pointer arguments")
+	}
+}
+
+func TestParamName(t *testing.T) {
+	var T = struct {
+		A string `exo:"a" json:"json_a"`
+		B string `json:"b,omitempty"`
+		C string
+		D string `json:"-"`
+	}{}
+
+	expected := []string{"a", "b", "C", ""}
+	for i, field := range exo.StructFields(T) {
+		if name := exo.ParamName(field); name != expected[i] {
+			t.Errorf("ParamName should return %q, got: %q", expected[i], name)
+		}
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	u := User{Name: "bob", Age: 30}
+
+	got, err := exo.FromMap[User](exo.ToMap(u))
+	if err != nil || got != u {
+		t.Errorf("FromMap should round-trip ToMap, got: %v %v", got, err)
+	}
+
+	got, err = exo.FromMap[User](map[string]interface{}{"Name": "bob"})
+	if err != nil || got.Name != "bob" || got.Age != 0 {
+		t.Errorf("FromMap should keep the zero value on missing keys, got: %v %v", got, err)
+	}
+
+	if _, err := exo.FromMap[User](map[string]interface{}{"Age": "30"}); err == nil {
+		t.Errorf("FromMap should return an error on type mismatch")
+	}
+}
+
+type Owner struct {
+	Name string
+	Tags []string
+}
+
+type Record struct {
+	Owner  Owner
+	Meta   map[string]int
+	Parent *Owner
+}
+
+func TestClone(t *testing.T) {
+	orig := Record{
+		Owner:  Owner{Name: "bob", Tags: []string{"a"}},
+		Meta:   map[string]int{"a": 1},
+		Parent: &Owner{Name: "alice"},
+	}
+
+	clone := exo.Clone(orig)
+
+	if !reflect.DeepEqual(orig, clone) {
+		t.Errorf("Clone should return an equal value, got: %v", clone)
+	}
+
+	clone.Owner.Name = "carl"
+	clone.Owner.Tags[0] = "z"
+	clone.Meta["a"] = 2
+	clone.Parent.Name = "dave"
+
+	if orig.Owner.Name != "bob" || orig.Owner.Tags[0] != "a" || orig.Meta["a"] != 1 || orig.Parent.Name != "alice" {
+		t.Errorf("Clone shouldn't share storage with the original, got: %v", orig)
+	}
+}
+
+type Base struct {
+	ID   int
+	Name string
+}
+
+type Member struct {
+	Base
+	Name  string
+	Email string
+}
+
+func TestStructFieldsEmbedded(t *testing.T) {
+	fields := exo.StructFields(Member{})
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+
+	if expected := []string{"Name", "Email", "ID"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("StructFields should promote embedded fields preferring the outer ones, got: %v", names)
+	}
+
+	m := Member{Base: Base{ID: 1, Name: "inner"}, Name: "outer"}
+	if got := exo.ToMap(m); got["ID"] != 1 || got["Name"] != "outer" {
+		t.Errorf("ToMap should read promoted fields, got: %v", got)
+	}
+}
+
+type Account struct {
+	*Base
+	Email string
+}
+
+func TestStructFieldsEmbeddedPointer(t *testing.T) {
+	fields := exo.StructFields(Account{})
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+
+	if expected := []string{"Email", "ID", "Name"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("StructFields should promote fields of embedded pointers, got: %v", names)
+	}
+
+	if got := exo.ToMap(Account{Email: "a@b.c"}); !reflect.DeepEqual(got, map[string]interface{}{"Email": "a@b.c"}) {
+		t.Errorf("ToMap should skip fields of nil embedded pointers, got: %v", got)
+	}
+
+	if got := exo.ToMap(Account{Base: &Base{ID: 1}}); got["ID"] != 1 {
+		t.Errorf("ToMap should read fields of embedded pointers, got: %v", got)
+	}
+
+	a, err := exo.FromMap[Account](map[string]interface{}{"ID": 2})
+	if err != nil || a.Base == nil || a.ID != 2 {
+		t.Errorf("FromMap should allocate nil embedded pointers, got: %v %v", a, err)
+	}
+
+	var b Account
+	if err := exo.ApplyToAny(&b, map[string]interface{}{"Name": "bob"}); err != nil || b.Base == nil || b.Name != "bob" {
+		t.Errorf("ApplyToAny should allocate nil embedded pointers, got: %v %v", b, err)
+	}
+
+	if diff := exo.Diff(Account{}, Account{Base: &Base{ID: 3}}); !reflect.DeepEqual(diff, map[string]interface{}{"ID": 3}) {
+		t.Errorf("Diff should compare fields of nil embedded pointers as zero values, got: %v", diff)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	u := User{Name: "bob", Age: 30}
+
+	if diff := exo.Diff(u, u); len(diff) != 0 {
+		t.Errorf("Diff should be empty for equal structs, got: %v", diff)
+	}
+
+	if diff := exo.Diff(u, User{Name: "bob", Age: 31}); !reflect.DeepEqual(diff, map[string]interface{}{"Age": 31}) {
+		t.Errorf("Diff should return the changed field, got: %v", diff)
+	}
+
+	if diff := exo.Diff(&u, &User{Name: "carl", Age: 31}); !reflect.DeepEqual(diff, map[string]interface{}{"Name": "carl", "Age": 31}) {
+		t.Errorf("Diff should return every changed field, got: %v", diff)
+	}
+}